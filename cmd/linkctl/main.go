@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/app"
+	"github.com/oziev02/Shortener/internal/application/usecase"
+	"github.com/oziev02/Shortener/internal/config"
+	"github.com/oziev02/Shortener/internal/domain/service"
+	"github.com/oziev02/Shortener/internal/infrastructure/database"
+)
+
+const usage = `Usage:
+  linkctl import -file links.csv [-format csv|json] [-dry-run]
+  linkctl export [-format csv|json] [-out links.csv]`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Загружаем конфигурацию из переменных окружения и .env файла
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	switch os.Args[1] {
+	case "import":
+		err = runImport(cfg, os.Args[2:])
+	case "export":
+		err = runExport(cfg, os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runImport импортирует ссылки из файла и печатает отчёт в JSON
+func runImport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "Path to CSV or JSON file")
+	format := fs.String("format", "", "File format: csv or json (defaults to file extension)")
+	dryRun := fs.Bool("dry-run", false, "Validate rows without creating links")
+	dbDSN := fs.String("db", cfg.DatabaseDSN, "Database DSN")
+	baseURL := fs.String("base-url", cfg.BaseURL, "Base URL for short links")
+	_ = fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	db, err := database.NewPostgresDB(*dbDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	linkRepo := database.NewLinkRepository(db)
+	domainRuleRepo := database.NewDomainRuleRepository(db)
+	shortenerService := service.NewShortenerService(*baseURL)
+	normalizer := service.NewURLNormalizer(cfg.NormalizeSortQuery, cfg.NormalizeStripParams)
+	urlPolicy := app.NewURLPolicy(cfg)
+	domainRulesUC := usecase.NewDomainRulesUseCase(domainRuleRepo, cfg.DomainAllowlistOnly, cfg.DomainRulesRefresh)
+	campaignRepo := database.NewCampaignRepository(db)
+	utmTemplateRepo := database.NewUTMTemplateRepository(db)
+	aliasPolicy, err := app.NewAliasPolicy(cfg)
+	if err != nil {
+		return err
+	}
+	shortenUC := usecase.NewShortenUseCase(linkRepo, shortenerService, nil, nil, normalizer, urlPolicy, domainRulesUC, campaignRepo, utmTemplateRepo, aliasPolicy)
+	importUC := usecase.NewImportUseCase(shortenUC)
+
+	records, err := importUC.Parse(*format, f)
+	if err != nil {
+		return err
+	}
+
+	job, err := importUC.Execute(context.Background(), *format, records, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(job); err != nil {
+		return err
+	}
+
+	if job.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", job.Failed, job.Total)
+	}
+	return nil
+}
+
+// runExport выгружает все ссылки в файл или stdout
+func runExport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", usecase.FormatCSV, "Output format: csv or json")
+	out := fs.String("out", "", "Output file (defaults to stdout)")
+	dbDSN := fs.String("db", cfg.DatabaseDSN, "Database DSN")
+	baseURL := fs.String("base-url", cfg.BaseURL, "Base URL for short links")
+	_ = fs.Parse(args)
+
+	db, err := database.NewPostgresDB(*dbDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	linkRepo := database.NewLinkRepository(db)
+	shortenerService := service.NewShortenerService(*baseURL)
+	exportUC := usecase.NewExportUseCase(linkRepo, shortenerService)
+
+	return exportUC.Execute(context.Background(), *format, w)
+}
@@ -2,20 +2,32 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	// Встроенная база часовых поясов для правил расписания в контейнерах без tzdata
+	_ "time/tzdata"
 
+	"github.com/oziev02/Shortener/internal/app"
 	"github.com/oziev02/Shortener/internal/application/usecase"
 	"github.com/oziev02/Shortener/internal/config"
 	"github.com/oziev02/Shortener/internal/domain/service"
 	"github.com/oziev02/Shortener/internal/infrastructure/cache"
 	"github.com/oziev02/Shortener/internal/infrastructure/database"
+	"github.com/oziev02/Shortener/internal/infrastructure/geoip"
+	"github.com/oziev02/Shortener/internal/infrastructure/healthcheck"
 	httphandler "github.com/oziev02/Shortener/internal/infrastructure/http"
+	"github.com/oziev02/Shortener/internal/infrastructure/httpclient"
+	"github.com/oziev02/Shortener/internal/infrastructure/opengraph"
+	"github.com/oziev02/Shortener/internal/infrastructure/qrcode"
+	"github.com/oziev02/Shortener/internal/infrastructure/ratelimit"
 )
 
 func main() {
@@ -44,6 +56,10 @@ func main() {
 
 	// Подключение к Redis (опционально)
 	var cacheInstance usecase.Cache
+	// Хранилище ключей идемпотентности работает и без Redis (в памяти процесса)
+	var idempotencyStore usecase.Cache = cache.NewMemoryCache(usecase.IdempotencyTTL)
+	// Лимиты запросов хранятся в памяти на одном узле или в Redis, если он подключён
+	var limiter ratelimit.Limiter = ratelimit.NewMemoryLimiter()
 	if *enableRedis {
 		redisCache, err := cache.NewRedisCache(*redisAddr, *redisPassword, 0, cfg.RedisTTL)
 		if err != nil {
@@ -52,25 +68,129 @@ func main() {
 			defer redisCache.Close()
 			log.Println("Redis cache enabled")
 			cacheInstance = redisCache
+			idempotencyStore = redisCache
+			limiter = ratelimit.NewRedisLimiter(redisCache.Client())
 		}
 	}
 
 	// Инициализация репозиториев
 	linkRepo := database.NewLinkRepository(db)
 	clickRepo := database.NewClickRepository(db)
+	domainRuleRepo := database.NewDomainRuleRepository(db)
+	conversionRepo := database.NewConversionRepository(db)
+	revisionRepo := database.NewLinkRevisionRepository(db)
+	campaignRepo := database.NewCampaignRepository(db)
+	tagRepo := database.NewTagRepository(db)
+	utmTemplateRepo := database.NewUTMTemplateRepository(db)
+	pageRepo := database.NewPageRepository(db)
+	healthRepo := database.NewHealthRepository(db)
+	healthSubscriptionRepo := database.NewHealthSubscriptionRepository(db)
 
 	// Инициализация сервисов
 	shortenerService := service.NewShortenerService(*baseURL)
+	normalizer := service.NewURLNormalizer(cfg.NormalizeSortQuery, cfg.NormalizeStripParams)
+	urlPolicy := app.NewURLPolicy(cfg)
+	urlSigner, err := newURLSigner(cfg)
+	if err != nil {
+		log.Fatalf("Invalid signing keys: %v", err)
+	}
+	// Без таблицы GeoIP гео-правила не срабатывают и используется основной адрес
+	var geoResolver usecase.GeoResolver
+	if cfg.GeoIPFile != "" {
+		resolver, err := geoip.LoadCSV(cfg.GeoIPFile)
+		if err != nil {
+			log.Fatalf("Failed to load GeoIP table: %v", err)
+		}
+		geoResolver = resolver
+		log.Println("GeoIP table loaded")
+	}
 
 	// Инициализация use cases
-	shortenUC := usecase.NewShortenUseCase(linkRepo, shortenerService, cacheInstance)
-	redirectUC := usecase.NewRedirectUseCase(linkRepo, clickRepo, cacheInstance)
-	analyticsUC := usecase.NewAnalyticsUseCase(linkRepo, clickRepo, cacheInstance)
+	domainRulesUC := usecase.NewDomainRulesUseCase(domainRuleRepo, cfg.DomainAllowlistOnly, cfg.DomainRulesRefresh)
+	// Проверка правил при редиректе включается отдельно
+	var redirectDomainRules *usecase.DomainRulesUseCase
+	if cfg.DomainRulesOnRedirect {
+		redirectDomainRules = domainRulesUC
+	}
+	aliasPolicy, err := app.NewAliasPolicy(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize alias policy: %v", err)
+	}
+	shortenUC := usecase.NewShortenUseCase(linkRepo, shortenerService, cacheInstance, idempotencyStore, normalizer, urlPolicy, domainRulesUC, campaignRepo, utmTemplateRepo, aliasPolicy)
+	redirectUC := usecase.NewRedirectUseCase(linkRepo, clickRepo, cacheInstance, redirectDomainRules, urlSigner, geoResolver)
+	analyticsUC := usecase.NewAnalyticsUseCase(linkRepo, clickRepo, conversionRepo, cacheInstance)
+	importUC := usecase.NewImportUseCase(shortenUC)
+	exportUC := usecase.NewExportUseCase(linkRepo, shortenerService)
+	signUC := usecase.NewSignUseCase(linkRepo, shortenerService, urlSigner)
+	qrEncoder, err := newQREncoder(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize QR encoder: %v", err)
+	}
+	qrUC := usecase.NewQRUseCase(linkRepo, shortenerService, qrEncoder, cacheInstance)
+	conversionUC := usecase.NewConversionUseCase(linkRepo, conversionRepo)
+	linkEditUC := usecase.NewLinkEditUseCase(linkRepo, revisionRepo, shortenUC, cacheInstance)
+	catalogUC := usecase.NewCatalogUseCase(linkRepo, campaignRepo, tagRepo, cacheInstance)
+	utmUC := usecase.NewUTMUseCase(utmTemplateRepo, clickRepo)
+	pageUC := usecase.NewPageUseCase(pageRepo, linkRepo, clickRepo, cacheInstance)
+	// Ручная проверка доступна всегда, фоновая запускается только при HEALTH_CHECK_ENABLED
+	// Исходящие запросы к адресам пользователей проверяют адрес при каждом подключении
+	healthClient := httpclient.New(cfg.HealthCheckTimeout, urlPolicy)
+	healthUC := usecase.NewHealthUseCase(linkRepo, healthRepo, healthSubscriptionRepo,
+		healthcheck.NewProber(healthClient, urlPolicy, cfg.HealthCheckHostDelay),
+		healthcheck.NewWebhookNotifier(healthClient),
+		urlPolicy, cfg.HealthCheckInterval, cfg.HealthCheckConcurrency)
+	// Без OPEN_GRAPH_FETCH_ENABLED превью строится только из полей, заданных вручную
+	var openGraphFetcher usecase.OpenGraphFetcher
+	if cfg.OpenGraphFetchEnabled {
+		openGraphFetcher = opengraph.NewFetcher(httpclient.New(cfg.OpenGraphFetchTimeout, urlPolicy), urlPolicy)
+	}
+	openGraphUC := usecase.NewOpenGraphUseCase(linkRepo, openGraphFetcher, cacheInstance)
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	if cfg.HealthCheckEnabled {
+		go healthUC.Run(backgroundCtx)
+		log.Println("Destination health checks enabled")
+	}
+
+	// Определение IP клиента с учётом доверенных прокси
+	clientIPResolver, err := httphandler.NewClientIPResolver(cfg.TrustedProxies, cfg.TrustedProxyHeader)
+	if err != nil {
+		log.Fatalf("Failed to configure trusted proxies: %v", err)
+	}
+
+	// Подпись cookie для защищённых паролем ссылок
+	cookieSecret := []byte(cfg.LinkCookieSecret)
+	if len(cookieSecret) == 0 {
+		cookieSecret = make([]byte, 32)
+		if _, err := rand.Read(cookieSecret); err != nil {
+			log.Fatalf("Failed to generate cookie secret: %v", err)
+		}
+		log.Println("Warning: LINK_COOKIE_SECRET is not set, password cookies will not survive restarts")
+	}
+	// Попытки ввода пароля ограничиваются вместе с остальными лимитами
+	var passwordLimiter ratelimit.Limiter
+	if cfg.RateLimitEnabled {
+		passwordLimiter = limiter
+	}
+	linkUnlocker := httphandler.NewLinkUnlocker(cookieSecret, cfg.LinkUnlockTTL, passwordLimiter,
+		rateLimitPolicy("password", cfg.RateLimitPassword))
+
+	wellKnownFiles, err := httphandler.LoadWellKnownFiles(cfg.AppleAppSiteAssociationFile, cfg.AssetLinksFile)
+	if err != nil {
+		log.Fatalf("Failed to load app association files: %v", err)
+	}
 
 	// Инициализация HTTP handler с логгером
 	logger := httphandler.NewStdLogger()
-	handler := httphandler.NewHandler(shortenUC, redirectUC, analyticsUC, logger)
-	router := httphandler.NewRouter(handler)
+	handler := httphandler.NewHandler(shortenUC, redirectUC, analyticsUC, importUC, exportUC, domainRulesUC, signUC, qrUC, conversionUC, linkEditUC, catalogUC, utmUC, pageUC, healthUC, openGraphUC, clientIPResolver, linkUnlocker, wellKnownFiles, logger)
+	if !cfg.RateLimitEnabled {
+		limiter = nil
+	}
+	router := httphandler.NewRouter(handler, cfg.AdminToken, limiter, httphandler.RateLimitPolicies{
+		Create:    rateLimitPolicy("create", cfg.RateLimitCreate),
+		Analytics: rateLimitPolicy("analytics", cfg.RateLimitAnalytics),
+		Redirect:  rateLimitPolicy("redirect", cfg.RateLimitRedirect),
+	})
 	mux := router.SetupRoutes()
 
 	// Настройка сервера
@@ -96,6 +216,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopBackground()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -105,3 +226,50 @@ func main() {
 
 	log.Println("Server exited")
 }
+
+// newURLSigner создаёт подписчика URL из списка ключей "kid:secret".
+// Без ключей подпись отключена. Если активный ключ не указан, используется первый из списка.
+func newURLSigner(cfg *config.Config) (*service.URLSigner, error) {
+	if len(cfg.SigningKeys) == 0 {
+		return nil, nil
+	}
+
+	keys := make(map[string][]byte, len(cfg.SigningKeys))
+	activeKey := cfg.SigningActiveKey
+	for i, entry := range cfg.SigningKeys {
+		kid, secret, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("signing key #%d must have the form kid:secret", i+1)
+		}
+		if _, exists := keys[kid]; exists {
+			return nil, fmt.Errorf("duplicate signing key %q", kid)
+		}
+		keys[kid] = []byte(secret)
+		if activeKey == "" {
+			activeKey = kid
+		}
+	}
+	return service.NewURLSigner(keys, activeKey)
+}
+
+// newQREncoder создаёт кодировщик QR-кодов с логотипом из QR_LOGO_FILE, если он задан
+func newQREncoder(cfg *config.Config) (*qrcode.Encoder, error) {
+	if cfg.QRLogoFile == "" {
+		return qrcode.NewEncoder(nil)
+	}
+	logo, err := qrcode.LoadLogo(cfg.QRLogoFile)
+	if err != nil {
+		return nil, err
+	}
+	return qrcode.NewEncoder(logo)
+}
+
+// rateLimitPolicy преобразует лимит из конфигурации в политику лимитера
+func rateLimitPolicy(name string, limit config.RateLimit) ratelimit.Policy {
+	return ratelimit.Policy{
+		Name:   name,
+		Limit:  limit.Limit,
+		Period: limit.Period,
+		Burst:  limit.Burst,
+	}
+}
@@ -0,0 +1,59 @@
+// Package app собирает зависимости, общие для сервера и CLI, по конфигурации
+package app
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/config"
+	"github.com/oziev02/Shortener/internal/domain/service"
+	httphandler "github.com/oziev02/Shortener/internal/infrastructure/http"
+)
+
+// NewURLPolicy создаёт политику защиты от SSRF по конфигурации
+func NewURLPolicy(cfg *config.Config) *service.URLPolicy {
+	var resolver service.Resolver
+	if cfg.SSRFResolveHosts {
+		resolver = net.DefaultResolver
+	}
+	return service.NewURLPolicy(cfg.SSRFBlockPrivate, resolver)
+}
+
+// NewAliasPolicy создаёт политику кастомных алиасов. К зарезервированным словам
+// добавляются пути маршрутов и ALIAS_RESERVED, к оскорбительным — слова из ALIAS_PROFANITY_FILE.
+func NewAliasPolicy(cfg *config.Config) (*service.AliasPolicy, error) {
+	var reserved []string
+	reserved = append(reserved, service.DefaultReservedAliases...)
+	reserved = append(reserved, httphandler.RoutePrefixes()...)
+	reserved = append(reserved, cfg.AliasReserved...)
+
+	var profanity []string
+	profanity = append(profanity, service.DefaultProfanity...)
+	if cfg.AliasProfanityFile != "" {
+		words, err := readWordList(cfg.AliasProfanityFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read profanity file: %w", err)
+		}
+		profanity = append(profanity, words...)
+	}
+
+	return service.NewAliasPolicy(cfg.AliasMinLength, cfg.AliasMaxLength, cfg.AliasLowercase, reserved, profanity), nil
+}
+
+// readWordList читает файл со словами по одному на строку; пустые строки и строки с # пропускаются
+func readWordList(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var words []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words, nil
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// aliasTransliteration таблица транслитерации кириллицы в латиницу
+var aliasTransliteration = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// Слова, из которых составляются варианты занятого алиаса
+var (
+	aliasPrefixes = []string{"get", "my", "go", "try"}
+	aliasSuffixes = []string{"now", "app", "hq", "online", "info"}
+)
+
+// AliasTakenError возвращается, когда кастомный алиас занят. Содержит свободные варианты алиаса.
+type AliasTakenError struct {
+	Alias       string
+	Suggestions []string
+}
+
+// Error возвращает текст ErrAliasExists
+func (e *AliasTakenError) Error() string {
+	return ErrAliasExists.Error()
+}
+
+// Unwrap позволяет сравнивать ошибку с ErrAliasExists через errors.Is
+func (e *AliasTakenError) Unwrap() error {
+	return ErrAliasExists
+}
+
+// AliasCheckResponse результат проверки алиаса
+type AliasCheckResponse struct {
+	Alias       string   `json:"alias"`
+	Available   bool     `json:"available"`
+	Suggestions []string `json:"suggestions"`
+	// Reason причина недоступности: ErrAliasExists или ошибка политики алиасов
+	Reason error `json:"-"`
+}
+
+// CheckAlias проверяет, можно ли создать ссылку с алиасом. Если алиас занят или не проходит
+// политику алиасов, возвращает до limit свободных вариантов. Алиас и все варианты
+// проверяются одним запросом к БД.
+func (uc *ShortenUseCase) CheckAlias(ctx context.Context, alias string, limit int) (*AliasCheckResponse, error) {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return nil, fmt.Errorf("%w: alias is required", ErrInvalidAlias)
+	}
+
+	resp := &AliasCheckResponse{Alias: alias, Suggestions: []string{}}
+	normalized, policyErr := uc.normalizeAlias(alias)
+	candidates := uc.aliasCandidates(alias, normalized)
+
+	lookup := candidates
+	if policyErr == nil {
+		resp.Alias = normalized
+		lookup = append([]string{normalized}, candidates...)
+	}
+	taken, err := uc.takenAliases(ctx, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case policyErr != nil:
+		resp.Reason = policyErr
+	case taken[normalized]:
+		resp.Reason = ErrAliasExists
+	default:
+		resp.Available = true
+		return resp, nil
+	}
+
+	resp.Suggestions = pickAliases(candidates, taken, limit)
+	return resp, nil
+}
+
+// SuggestAliases возвращает до limit свободных вариантов алиаса: с другими разделителями,
+// словами-приставками, годом, номером и случайным числом. Кириллица транслитерируется.
+func (uc *ShortenUseCase) SuggestAliases(ctx context.Context, alias string, limit int) ([]string, error) {
+	normalized, err := uc.normalizeAlias(alias)
+	if err != nil {
+		normalized = ""
+	}
+	candidates := uc.aliasCandidates(alias, normalized)
+
+	taken, err := uc.takenAliases(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	return pickAliases(candidates, taken, limit), nil
+}
+
+// aliasTaken дополняет ErrAliasExists свободными вариантами алиаса. Остальные ошибки
+// возвращаются как есть; если подобрать варианты не удалось, возвращается исходная ошибка.
+func (uc *ShortenUseCase) aliasTaken(ctx context.Context, alias string, err error) error {
+	if !errors.Is(err, ErrAliasExists) {
+		return err
+	}
+	suggestions, suggestErr := uc.SuggestAliases(ctx, alias, DefaultAliasSuggestions)
+	if suggestErr != nil {
+		return err
+	}
+	return &AliasTakenError{Alias: alias, Suggestions: suggestions}
+}
+
+// takenAliases возвращает множество занятых алиасов из списка
+func (uc *ShortenUseCase) takenAliases(ctx context.Context, aliases []string) (map[string]bool, error) {
+	taken, err := uc.linkRepo.TakenAliases(ctx, aliases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check aliases: %w", err)
+	}
+	result := make(map[string]bool, len(taken))
+	for _, alias := range taken {
+		result[alias] = true
+	}
+	return result, nil
+}
+
+// aliasCandidates составляет варианты алиаса в порядке предпочтения. Варианты проходят
+// политику алиасов, повторы и сам алиас (exclude) исключаются.
+func (uc *ShortenUseCase) aliasCandidates(alias, exclude string) []string {
+	maxLength := uc.aliasPolicy.MaxLength()
+	base := fitAlias(slugifyAlias(alias), maxLength)
+	if base == "" {
+		return nil
+	}
+
+	separator := "-"
+	if strings.Contains(base, "_") && !strings.Contains(base, "-") {
+		separator = "_"
+	}
+	withPrefix := func(prefix string) string {
+		return prefix + separator + fitAlias(base, maxLength-len(prefix)-len(separator))
+	}
+	withSuffix := func(suffix string) string {
+		return fitAlias(base, maxLength-len(suffix)-len(separator)) + separator + suffix
+	}
+
+	raw := []string{base}
+	if strings.ContainsAny(base, "-_") {
+		raw = append(raw,
+			strings.NewReplacer("-", "_", "_", "-").Replace(base),
+			strings.NewReplacer("-", "", "_", "").Replace(base),
+		)
+	}
+	raw = append(raw, withSuffix("2"), withSuffix("3"))
+	for _, prefix := range aliasPrefixes {
+		if !strings.HasPrefix(strings.ToLower(base), prefix+separator) {
+			raw = append(raw, withPrefix(prefix))
+		}
+	}
+	for _, suffix := range aliasSuffixes {
+		raw = append(raw, withSuffix(suffix))
+	}
+	raw = append(raw, withSuffix(strconv.Itoa(time.Now().Year())))
+	for n := 4; n <= 9; n++ {
+		raw = append(raw, withSuffix(strconv.Itoa(n)))
+	}
+	// Случайные номера почти всегда свободны, поэтому идут последними
+	for i := 0; i < 3; i++ {
+		if n, err := rand.Int(rand.Reader, big.NewInt(900)); err == nil {
+			raw = append(raw, withSuffix(strconv.FormatInt(n.Int64()+100, 10)))
+		}
+	}
+
+	seen := map[string]bool{exclude: true}
+	candidates := make([]string, 0, len(raw))
+	for _, candidate := range raw {
+		normalized, err := uc.aliasPolicy.Normalize(candidate)
+		if err != nil || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		candidates = append(candidates, normalized)
+		if len(candidates) == MaxAliasCandidates {
+			break
+		}
+	}
+	return candidates
+}
+
+// pickAliases возвращает до limit свободных алиасов из candidates
+func pickAliases(candidates []string, taken map[string]bool, limit int) []string {
+	if limit <= 0 {
+		limit = DefaultAliasSuggestions
+	}
+	if limit > MaxAliasSuggestions {
+		limit = MaxAliasSuggestions
+	}
+
+	result := make([]string, 0, limit)
+	for _, candidate := range candidates {
+		if len(result) == limit {
+			break
+		}
+		if !taken[candidate] {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+// slugifyAlias приводит строку к символам алиаса: кириллица транслитерируется,
+// остальные недопустимые символы заменяются на "-"
+func slugifyAlias(value string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(value) {
+		if r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_') {
+			b.WriteRune(r)
+		} else if latin, ok := aliasTransliteration[unicode.ToLower(r)]; ok {
+			b.WriteString(latin)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+
+	// Схлопываем повторяющиеся дефисы и убираем разделители по краям
+	slug := b.String()
+	for strings.Contains(slug, "--") {
+		slug = strings.ReplaceAll(slug, "--", "-")
+	}
+	return strings.Trim(slug, "-_")
+}
+
+// fitAlias укорачивает алиас до length символов; при length <= 0 алиас не меняется
+func fitAlias(alias string, length int) string {
+	if length <= 0 || len(alias) <= length {
+		return alias
+	}
+	return strings.TrimRight(alias[:length], "-_")
+}
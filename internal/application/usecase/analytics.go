@@ -10,21 +10,24 @@ import (
 
 // AnalyticsUseCase обрабатывает запросы аналитики
 type AnalyticsUseCase struct {
-	linkRepo  repository.LinkRepository
-	clickRepo repository.ClickRepository
-	cache     Cache
+	linkRepo       repository.LinkRepository
+	clickRepo      repository.ClickRepository
+	conversionRepo repository.ConversionRepository
+	cache          Cache
 }
 
 // NewAnalyticsUseCase создаёт новый use case
 func NewAnalyticsUseCase(
 	linkRepo repository.LinkRepository,
 	clickRepo repository.ClickRepository,
+	conversionRepo repository.ConversionRepository,
 	cache Cache,
 ) *AnalyticsUseCase {
 	return &AnalyticsUseCase{
-		linkRepo:  linkRepo,
-		clickRepo: clickRepo,
-		cache:     cache,
+		linkRepo:       linkRepo,
+		clickRepo:      clickRepo,
+		conversionRepo: conversionRepo,
+		cache:          cache,
 	}
 }
 
@@ -45,6 +48,10 @@ func (uc *AnalyticsUseCase) Execute(ctx context.Context, shortURL string) (*enti
 		return nil, fmt.Errorf("failed to get analytics: %w", err)
 	}
 
+	if err := uc.addConversions(ctx, analytics); err != nil {
+		return nil, err
+	}
+
 	// Получаем последние переходы
 	recentClicks, err := uc.clickRepo.GetByLinkID(ctx, link.ID, 10)
 	if err == nil && recentClicks != nil {
@@ -56,3 +63,39 @@ func (uc *AnalyticsUseCase) Execute(ctx context.Context, shortURL string) (*enti
 
 	return analytics, nil
 }
+
+// addConversions дополняет статистику вариантов числом конверсий и их долей
+func (uc *AnalyticsUseCase) addConversions(ctx context.Context, analytics *entity.Analytics) error {
+	if uc.conversionRepo == nil {
+		return nil
+	}
+
+	conversions, err := uc.conversionRepo.CountByVariant(ctx, analytics.LinkID)
+	if err != nil {
+		return fmt.Errorf("failed to get conversions: %w", err)
+	}
+
+	for variant, count := range conversions {
+		analytics.TotalConversions += count
+		// Конверсии ссылок без вариантов учитываются только в общем числе
+		if variant == "" {
+			continue
+		}
+		if analytics.ByVariant == nil {
+			analytics.ByVariant = make(map[string]*entity.VariantStats)
+		}
+		stats, ok := analytics.ByVariant[variant]
+		if !ok {
+			stats = &entity.VariantStats{}
+			analytics.ByVariant[variant] = stats
+		}
+		stats.Conversions = count
+	}
+
+	for _, stats := range analytics.ByVariant {
+		if stats.Clicks > 0 {
+			stats.ConversionRate = float64(stats.Conversions) / float64(stats.Clicks)
+		}
+	}
+	return nil
+}
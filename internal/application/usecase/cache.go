@@ -1,10 +1,22 @@
 package usecase
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Cache интерфейс для кэширования
 type Cache interface {
 	Get(ctx context.Context, key string, dest interface{}) error
 	Set(ctx context.Context, key string, value interface{}) error
 	Delete(ctx context.Context, key string) error
+	// SetWithTTL сохраняет значение с явным временем жизни
+	SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// SetIfNotExists атомарно сохраняет значение, только если ключа ещё нет
+	SetIfNotExists(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+}
+
+// linkCacheKey возвращает ключ кэша ссылки по короткому коду
+func linkCacheKey(shortURL string) string {
+	return "link:" + shortURL
 }
@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// catalogNamePattern допустимые имена тегов и кампаний
+var catalogNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// CatalogUseCase управляет каталогом ссылок: тегами, кампаниями и выборками по ним
+type CatalogUseCase struct {
+	linkRepo     repository.LinkRepository
+	campaignRepo repository.CampaignRepository
+	tagRepo      repository.TagRepository
+	cache        Cache
+}
+
+// NewCatalogUseCase создаёт новый use case
+func NewCatalogUseCase(
+	linkRepo repository.LinkRepository,
+	campaignRepo repository.CampaignRepository,
+	tagRepo repository.TagRepository,
+	cache Cache,
+) *CatalogUseCase {
+	return &CatalogUseCase{
+		linkRepo:     linkRepo,
+		campaignRepo: campaignRepo,
+		tagRepo:      tagRepo,
+		cache:        cache,
+	}
+}
+
+// ListLinksRequest запрос списка ссылок
+type ListLinksRequest struct {
+	Tag      string
+	Campaign string
+	Limit    int
+	Offset   int
+}
+
+// ListLinksResponse страница списка ссылок
+type ListLinksResponse struct {
+	Links  []*entity.Link `json:"links"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// CreateCampaignRequest запрос на создание кампании
+type CreateCampaignRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// UpdateCampaignRequest запрос на изменение кампании
+type UpdateCampaignRequest struct {
+	Name        string `json:"-"`
+	Description string `json:"description"`
+}
+
+// ListLinks возвращает страницу ссылок, отфильтрованных по тегу и кампании
+func (uc *CatalogUseCase) ListLinks(ctx context.Context, req ListLinksRequest) (*ListLinksResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = DefaultListLimit
+	}
+	if req.Limit > MaxListLimit {
+		req.Limit = MaxListLimit
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	links, err := uc.linkRepo.List(ctx, repository.LinkFilter{
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+		Tag:      strings.ToLower(strings.TrimSpace(req.Tag)),
+		Campaign: strings.ToLower(strings.TrimSpace(req.Campaign)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links: %w", err)
+	}
+	if links == nil {
+		links = []*entity.Link{}
+	}
+
+	return &ListLinksResponse{Links: links, Limit: req.Limit, Offset: req.Offset}, nil
+}
+
+// SetLinkTags заменяет теги ссылки и возвращает их в нормализованном виде
+func (uc *CatalogUseCase) SetLinkTags(ctx context.Context, shortURL string, tags []string) ([]string, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := uc.getLink(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.linkRepo.SetTags(ctx, link.ID, tags); err != nil {
+		return nil, fmt.Errorf("failed to set tags: %w", err)
+	}
+	uc.invalidateLink(ctx, shortURL)
+
+	return tags, nil
+}
+
+// SetLinkCampaign переносит ссылку в кампанию; пустое имя убирает ссылку из кампании
+func (uc *CatalogUseCase) SetLinkCampaign(ctx context.Context, shortURL string, campaign string) error {
+	campaign = strings.ToLower(strings.TrimSpace(campaign))
+	if campaign != "" {
+		if _, err := uc.GetCampaign(ctx, campaign); err != nil {
+			return err
+		}
+	}
+
+	link, err := uc.getLink(ctx, shortURL)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.linkRepo.SetCampaign(ctx, link.ID, campaign); err != nil {
+		return fmt.Errorf("failed to set campaign: %w", err)
+	}
+	uc.invalidateLink(ctx, shortURL)
+
+	return nil
+}
+
+// CreateCampaign создаёт кампанию
+func (uc *CatalogUseCase) CreateCampaign(ctx context.Context, req CreateCampaignRequest) (*entity.Campaign, error) {
+	name, err := normalizeCatalogName(req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCampaign, err)
+	}
+
+	campaign := &entity.Campaign{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		CreatedAt:   time.Now(),
+	}
+	if err := uc.campaignRepo.Create(ctx, campaign); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrCampaignExists
+		}
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// ListCampaigns возвращает все кампании
+func (uc *CatalogUseCase) ListCampaigns(ctx context.Context) ([]*entity.Campaign, error) {
+	campaigns, err := uc.campaignRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	if campaigns == nil {
+		campaigns = []*entity.Campaign{}
+	}
+	return campaigns, nil
+}
+
+// GetCampaign возвращает кампанию по имени
+func (uc *CatalogUseCase) GetCampaign(ctx context.Context, name string) (*entity.Campaign, error) {
+	campaign, err := uc.campaignRepo.GetByName(ctx, strings.ToLower(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	if campaign == nil {
+		return nil, ErrCampaignNotFound
+	}
+	return campaign, nil
+}
+
+// UpdateCampaign изменяет описание кампании
+func (uc *CatalogUseCase) UpdateCampaign(ctx context.Context, req UpdateCampaignRequest) (*entity.Campaign, error) {
+	campaign := &entity.Campaign{
+		Name:        strings.ToLower(req.Name),
+		Description: strings.TrimSpace(req.Description),
+	}
+
+	updated, err := uc.campaignRepo.Update(ctx, campaign)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update campaign: %w", err)
+	}
+	if !updated {
+		return nil, ErrCampaignNotFound
+	}
+
+	return uc.GetCampaign(ctx, campaign.Name)
+}
+
+// DeleteCampaign удаляет кампанию; ссылки остаются без кампании
+func (uc *CatalogUseCase) DeleteCampaign(ctx context.Context, name string) error {
+	deleted, err := uc.campaignRepo.Delete(ctx, strings.ToLower(name))
+	if err != nil {
+		return fmt.Errorf("failed to delete campaign: %w", err)
+	}
+	if !deleted {
+		return ErrCampaignNotFound
+	}
+	return nil
+}
+
+// CampaignAnalytics возвращает аналитику, просуммированную по всем ссылкам кампании
+func (uc *CatalogUseCase) CampaignAnalytics(ctx context.Context, name string) (*entity.CampaignAnalytics, error) {
+	campaign, err := uc.GetCampaign(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics, err := uc.campaignRepo.GetAnalytics(ctx, campaign.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign analytics: %w", err)
+	}
+	return analytics, nil
+}
+
+// ListTags возвращает теги с числом ссылок
+func (uc *CatalogUseCase) ListTags(ctx context.Context) ([]*entity.Tag, error) {
+	tags, err := uc.tagRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	if tags == nil {
+		tags = []*entity.Tag{}
+	}
+	return tags, nil
+}
+
+// DeleteTag снимает тег со всех ссылок и удаляет его
+func (uc *CatalogUseCase) DeleteTag(ctx context.Context, name string) error {
+	deleted, err := uc.tagRepo.Delete(ctx, strings.ToLower(name))
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	if !deleted {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// getLink получает ссылку из БД
+func (uc *CatalogUseCase) getLink(ctx context.Context, shortURL string) (*entity.Link, error) {
+	link, err := uc.linkRepo.GetByShortURL(ctx, shortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+	return link, nil
+}
+
+// invalidateLink удаляет ссылку из кэша, чтобы теги и кампания в ней не устарели
+func (uc *CatalogUseCase) invalidateLink(ctx context.Context, shortURL string) {
+	if uc.cache == nil {
+		return
+	}
+	if err := uc.cache.Delete(ctx, linkCacheKey(shortURL)); err != nil {
+		// Запись истечёт по TTL
+		_ = err
+	}
+}
+
+// normalizeCatalogName приводит имя тега или кампании к нижнему регистру и проверяет формат
+func normalizeCatalogName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !catalogNamePattern.MatchString(name) {
+		return "", fmt.Errorf("name %q must be 1-64 characters of a-z, 0-9, _ and -, starting with a letter or digit", name)
+	}
+	return name, nil
+}
+
+// normalizeTags нормализует теги, убирает повторы и сортирует их
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) > MaxTagsPerLink {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidTag, MaxTagsPerLink)
+	}
+
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name, err := normalizeCatalogName(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTag, err)
+		}
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
@@ -1,6 +1,127 @@
 package usecase
 
+import "time"
+
 const (
 	// DefaultShortURLLength длина короткого URL по умолчанию
 	DefaultShortURLLength = 8
+
+	// MaxURLLength максимальная длина URL
+	MaxURLLength = 2048
+
+	// ExportBatchSize количество ссылок, читаемых из БД за один запрос при экспорте
+	ExportBatchSize = 500
+
+	// ImportJobRetention время хранения завершённых задач импорта
+	ImportJobRetention = 24 * time.Hour
+
+	// IdempotencyTTL время хранения ответов для ключей идемпотентности
+	IdempotencyTTL = 24 * time.Hour
+
+	// IdempotencyLockTTL время, на которое ключ резервируется до завершения запроса
+	IdempotencyLockTTL = time.Minute
+
+	// MaxIdempotencyKeyLength максимальная длина ключа идемпотентности
+	MaxIdempotencyKeyLength = 255
+
+	// MinLinkPasswordLength минимальная длина пароля ссылки
+	MinLinkPasswordLength = 4
+
+	// MaxLinkPasswordLength максимальная длина пароля ссылки (ограничение bcrypt)
+	MaxLinkPasswordLength = 72
+
+	// DefaultSignatureTTL срок действия подписанного URL, если он не указан
+	DefaultSignatureTTL = 24 * time.Hour
+
+	// MaxSignatureTTL максимальный срок действия подписанного URL
+	MaxSignatureTTL = 365 * 24 * time.Hour
+
+	// MaxInterstitialSeconds максимальная задержка промежуточной страницы перед редиректом
+	MaxInterstitialSeconds = 60
+
+	// DefaultQRSize размер изображения QR-кода по умолчанию в пикселях
+	DefaultQRSize = 256
+
+	// MinQRSize и MaxQRSize допустимые размеры изображения QR-кода
+	MinQRSize = 64
+	MaxQRSize = 2048
+
+	// DefaultQRMargin ширина свободной зоны QR-кода по умолчанию в модулях
+	DefaultQRMargin = 4
+
+	// MaxQRMargin максимальная ширина свободной зоны QR-кода в модулях
+	MaxQRMargin = 16
+
+	// MaxTargetingRules максимальное число правил таргетинга у ссылки
+	MaxTargetingRules = 20
+
+	// MaxGeoRules максимальное число гео-правил у ссылки
+	MaxGeoRules = 50
+
+	// MaxRoutingRules максимальное число правил маршрутизации у ссылки
+	MaxRoutingRules = 20
+
+	// MaxVariants максимальное число вариантов A/B-теста
+	MaxVariants = 10
+
+	// MaxVariantWeight максимальный вес варианта A/B-теста
+	MaxVariantWeight = 1000
+
+	// MaxWindowCacheTTL максимальное время жизни записи кэша для ссылки с окном активности
+	MaxWindowCacheTTL = 30 * time.Minute
+
+	// DefaultListLimit размер страницы списка ссылок по умолчанию
+	DefaultListLimit = 50
+
+	// MaxListLimit максимальный размер страницы списка ссылок
+	MaxListLimit = 500
+
+	// MaxTagsPerLink максимальное число тегов у ссылки
+	MaxTagsPerLink = 20
+
+	// MaxUTMValueLength максимальная длина значения UTM-метки в байтах
+	MaxUTMValueLength = 256
+
+	// MaxPageItems максимальное число пунктов на странице ссылок
+	MaxPageItems = 50
+
+	// MaxPageTitleLength максимальная длина заголовка страницы и пункта в символах
+	MaxPageTitleLength = 200
+
+	// MaxPageDescriptionLength максимальная длина описания страницы в символах
+	MaxPageDescriptionLength = 1000
+
+	// MaxPageIconLength максимальная длина текстовой иконки пункта в символах
+	MaxPageIconLength = 16
+
+	// MaxOpenGraphTitleLength максимальная длина заголовка превью в символах
+	MaxOpenGraphTitleLength = 300
+
+	// MaxOpenGraphDescriptionLength максимальная длина описания превью в символах
+	MaxOpenGraphDescriptionLength = 1000
+
+	// HealthCheckTick период выбора ссылок для фоновой проверки доступности
+	HealthCheckTick = time.Minute
+
+	// HealthCheckBatchSize число ссылок, выбираемых для проверки за один запрос к БД
+	HealthCheckBatchSize = 100
+
+	// HealthFailureThreshold число неудачных проверок подряд, после которого адрес
+	// считается недоступным
+	HealthFailureThreshold = 2
+
+	// MaxHealthHistory число последних проверок, хранимых для каждой ссылки
+	MaxHealthHistory = 50
+
+	// DefaultAliasSuggestions число вариантов, предлагаемых вместо занятого алиаса
+	DefaultAliasSuggestions = 5
+
+	// MaxAliasSuggestions максимальное число вариантов алиаса в одном ответе
+	MaxAliasSuggestions = 20
+
+	// MaxAliasCandidates максимальное число вариантов алиаса, проверяемых одним запросом к БД
+	MaxAliasCandidates = 40
+
+	// QRCacheTTL время хранения сгенерированных QR-кодов в кэше
+	QRCacheTTL = 24 * time.Hour
 )
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// forbiddenAppSchemes схемы, которые нельзя использовать как адрес приложения:
+// они выполняют код или открывают локальные данные в браузере
+var forbiddenAppSchemes = map[string]bool{
+	"javascript": true,
+	"vbscript":   true,
+	"data":       true,
+	"blob":       true,
+	"file":       true,
+	"about":      true,
+}
+
+// DeepLinkTarget адреса страницы открытия приложения для платформы клиента
+type DeepLinkTarget struct {
+	// AppURL адрес, которым открывается приложение
+	AppURL string
+	// FallbackURL адрес перехода, если приложение не открылось
+	FallbackURL string
+}
+
+// checkDeepLink проверяет адреса открытия приложения. Адреса приложения могут иметь
+// любую схему, кроме опасных; адреса магазинов и веб-адрес проверяются как адреса правил.
+func (uc *ShortenUseCase) checkDeepLink(ctx context.Context, deepLink *entity.DeepLink) (*entity.DeepLink, error) {
+	if deepLink == nil {
+		return nil, nil
+	}
+
+	result := entity.DeepLink{
+		AppURL:          strings.TrimSpace(deepLink.AppURL),
+		IOSURL:          strings.TrimSpace(deepLink.IOSURL),
+		AndroidURL:      strings.TrimSpace(deepLink.AndroidURL),
+		IOSStoreURL:     strings.TrimSpace(deepLink.IOSStoreURL),
+		AndroidStoreURL: strings.TrimSpace(deepLink.AndroidStoreURL),
+		FallbackURL:     strings.TrimSpace(deepLink.FallbackURL),
+	}
+	if result.AppURL == "" {
+		return nil, fmt.Errorf("%w: app_url is required", ErrInvalidDeepLink)
+	}
+
+	appURLs := []struct {
+		name  string
+		value string
+	}{
+		{"app_url", result.AppURL},
+		{"ios_url", result.IOSURL},
+		{"android_url", result.AndroidURL},
+	}
+	for _, field := range appURLs {
+		if field.value == "" {
+			continue
+		}
+		if err := validateAppURL(field.value); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidDeepLink, field.name, err)
+		}
+		// Universal link без установленного приложения открывается в браузере как обычный адрес
+		if isWebURL(field.value) {
+			if err := uc.checkRuleURL(ctx, field.value); err != nil {
+				return nil, fmt.Errorf("deep_link: %s: %w", field.name, err)
+			}
+		}
+	}
+
+	webURLs := []struct {
+		name  string
+		value string
+	}{
+		{"ios_store_url", result.IOSStoreURL},
+		{"android_store_url", result.AndroidStoreURL},
+		{"fallback_url", result.FallbackURL},
+	}
+	for _, field := range webURLs {
+		if field.value == "" {
+			continue
+		}
+		if err := uc.checkRuleURL(ctx, field.value); err != nil {
+			return nil, fmt.Errorf("deep_link: %s: %w", field.name, err)
+		}
+	}
+
+	return &result, nil
+}
+
+// validateAppURL проверяет адрес открытия приложения
+func validateAppURL(rawURL string) error {
+	if len(rawURL) > MaxURLLength {
+		return errors.New("URL too long")
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme == "" {
+		return errors.New("URL scheme is required")
+	}
+	if forbiddenAppSchemes[scheme] {
+		return fmt.Errorf("scheme %q is not allowed", scheme)
+	}
+	if (scheme == "http" || scheme == "https") && u.Host == "" {
+		return errors.New("URL host is required")
+	}
+	return nil
+}
+
+// isWebURL проверяет, что адрес имеет схему http или https
+func isWebURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return scheme == "http" || scheme == "https"
+}
+
+// deepLinkTarget выбирает адреса открытия приложения для клиента. Страница открытия
+// показывается только браузерам iOS и Android; для остальных клиентов возвращается nil.
+// webURL — адрес перехода с учётом правил, он используется, если другие адреса не заданы.
+func (uc *RedirectUseCase) deepLinkTarget(ctx context.Context, deepLink *entity.DeepLink, userAgent, webURL string) (*DeepLinkTarget, error) {
+	ua := service.ParseUserAgent(userAgent)
+	if ua.DeviceType != service.DeviceMobile && ua.DeviceType != service.DeviceTablet {
+		return nil, nil
+	}
+
+	var appURL, storeURL string
+	switch ua.OS {
+	case service.OSiOS:
+		appURL, storeURL = deepLink.IOSURL, deepLink.IOSStoreURL
+	case service.OSAndroid:
+		appURL, storeURL = deepLink.AndroidURL, deepLink.AndroidStoreURL
+	default:
+		return nil, nil
+	}
+	if appURL == "" {
+		appURL = deepLink.AppURL
+	}
+
+	fallbackURL := storeURL
+	if fallbackURL == "" {
+		fallbackURL = deepLink.FallbackURL
+	}
+	if fallbackURL == "" {
+		fallbackURL = webURL
+	} else if err := uc.checkDomainRules(ctx, fallbackURL); err != nil {
+		// Адрес магазина или веб-адрес мог попасть под блокировку после создания ссылки
+		return nil, err
+	}
+
+	return &DeepLinkTarget{AppURL: appURL, FallbackURL: fallbackURL}, nil
+}
@@ -0,0 +1,431 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+const (
+	// maxDomainRulePatternLength максимальная длина шаблона правила
+	maxDomainRulePatternLength = 1024
+	// minHashPrefixLength минимальная длина hex-префикса хэша (4 байта, как в Safe Browsing)
+	minHashPrefixLength = 8
+)
+
+// CreateDomainRuleRequest запрос на создание правила
+type CreateDomainRuleRequest struct {
+	Action  entity.DomainRuleAction `json:"action"`
+	Kind    entity.DomainRuleKind   `json:"kind"`
+	Pattern string                  `json:"pattern"`
+	Comment string                  `json:"comment,omitempty"`
+}
+
+// DomainRulesUseCase проверяет целевые URL по правилам блокировки и разрешения.
+// Правила хранятся в БД и кэшируются в памяти; кэш сбрасывается при изменении правил
+// через этот use case и перечитывается не реже чем раз в refreshInterval,
+// чтобы изменения, сделанные на других узлах, тоже применялись.
+type DomainRulesUseCase struct {
+	ruleRepo        repository.DomainRuleRepository
+	allowlistOnly   bool
+	refreshInterval time.Duration
+
+	loadMu   sync.Mutex
+	mu       sync.RWMutex
+	compiled *compiledDomainRules
+	loadedAt time.Time
+}
+
+// NewDomainRulesUseCase создаёт новый use case.
+// Если allowlistOnly = true, разрешены только адреса, совпавшие с правилами allow.
+func NewDomainRulesUseCase(
+	ruleRepo repository.DomainRuleRepository,
+	allowlistOnly bool,
+	refreshInterval time.Duration,
+) *DomainRulesUseCase {
+	return &DomainRulesUseCase{
+		ruleRepo:        ruleRepo,
+		allowlistOnly:   allowlistOnly,
+		refreshInterval: refreshInterval,
+	}
+}
+
+// Check проверяет канонический URL; возвращает ErrDomainBlocked, если адрес запрещён
+func (uc *DomainRulesUseCase) Check(ctx context.Context, canonicalURL string) error {
+	rules, err := uc.current(ctx)
+	if err != nil {
+		return err
+	}
+
+	target, err := newRuleTarget(canonicalURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	// Правила allow имеют приоритет и позволяют делать исключения из блокировок
+	if rule := rules.allow.match(target); rule != nil {
+		return nil
+	}
+	if rule := rules.block.match(target); rule != nil {
+		return fmt.Errorf("%w: matched rule %d", ErrDomainBlocked, rule.ID)
+	}
+	if uc.allowlistOnly {
+		return fmt.Errorf("%w: host %s is not in allowlist", ErrDomainBlocked, target.host)
+	}
+
+	return nil
+}
+
+// List возвращает все правила
+func (uc *DomainRulesUseCase) List(ctx context.Context) ([]*entity.DomainRule, error) {
+	rules, err := uc.ruleRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domain rules: %w", err)
+	}
+	if rules == nil {
+		rules = []*entity.DomainRule{}
+	}
+	return rules, nil
+}
+
+// Create проверяет и сохраняет новое правило
+func (uc *DomainRulesUseCase) Create(ctx context.Context, req CreateDomainRuleRequest) (*entity.DomainRule, error) {
+	rule := &entity.DomainRule{
+		Action:    req.Action,
+		Kind:      req.Kind,
+		Pattern:   strings.TrimSpace(req.Pattern),
+		Comment:   req.Comment,
+		CreatedAt: time.Now(),
+	}
+
+	if rule.Action != entity.DomainRuleBlock && rule.Action != entity.DomainRuleAllow {
+		return nil, fmt.Errorf("%w: action must be block or allow", ErrInvalidDomainRule)
+	}
+
+	pattern, err := normalizeRulePattern(rule.Kind, rule.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDomainRule, err)
+	}
+	rule.Pattern = pattern
+
+	if err := uc.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create domain rule: %w", err)
+	}
+
+	uc.invalidate()
+	return rule, nil
+}
+
+// Delete удаляет правило
+func (uc *DomainRulesUseCase) Delete(ctx context.Context, id int64) error {
+	deleted, err := uc.ruleRepo.Delete(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete domain rule: %w", err)
+	}
+	if !deleted {
+		return ErrDomainRuleNotFound
+	}
+
+	uc.invalidate()
+	return nil
+}
+
+// invalidate сбрасывает кэш правил, следующая проверка перечитает их из БД
+func (uc *DomainRulesUseCase) invalidate() {
+	uc.mu.Lock()
+	uc.loadedAt = time.Time{}
+	uc.mu.Unlock()
+}
+
+// current возвращает актуальный набор правил, перечитывая его при необходимости.
+// Если БД недоступна, используется последний загруженный набор.
+func (uc *DomainRulesUseCase) current(ctx context.Context) (*compiledDomainRules, error) {
+	uc.mu.RLock()
+	rules, fresh := uc.compiled, time.Since(uc.loadedAt) < uc.refreshInterval
+	uc.mu.RUnlock()
+	if rules != nil && fresh {
+		return rules, nil
+	}
+
+	// Перечитывает правила только одна горутина, остальные ждут результата
+	uc.loadMu.Lock()
+	defer uc.loadMu.Unlock()
+
+	uc.mu.RLock()
+	rules, fresh = uc.compiled, time.Since(uc.loadedAt) < uc.refreshInterval
+	uc.mu.RUnlock()
+	if rules != nil && fresh {
+		return rules, nil
+	}
+
+	list, err := uc.ruleRepo.List(ctx)
+	if err != nil {
+		if rules != nil {
+			return rules, nil
+		}
+		return nil, fmt.Errorf("failed to load domain rules: %w", err)
+	}
+
+	compiled := compileDomainRules(list)
+
+	uc.mu.Lock()
+	uc.compiled = compiled
+	uc.loadedAt = time.Now()
+	uc.mu.Unlock()
+
+	return compiled, nil
+}
+
+// normalizeRulePattern проверяет шаблон и приводит его к виду, используемому при сопоставлении
+func normalizeRulePattern(kind entity.DomainRuleKind, pattern string) (string, error) {
+	if pattern == "" {
+		return "", fmt.Errorf("pattern is required")
+	}
+	if len(pattern) > maxDomainRulePatternLength {
+		return "", fmt.Errorf("pattern is too long")
+	}
+
+	switch kind {
+	case entity.DomainRuleExact:
+		return service.NormalizeHost(pattern)
+	case entity.DomainRuleWildcard:
+		if !strings.HasPrefix(pattern, "*.") {
+			return "", fmt.Errorf("wildcard pattern must start with *.")
+		}
+		host, err := service.NormalizeHost(strings.TrimPrefix(pattern, "*."))
+		if err != nil {
+			return "", err
+		}
+		return "*." + host, nil
+	case entity.DomainRuleRegex:
+		if _, err := regexp.Compile(pattern); err != nil {
+			return "", err
+		}
+		return pattern, nil
+	case entity.DomainRuleHashPrefix:
+		pattern = strings.ToLower(pattern)
+		if len(pattern) < minHashPrefixLength || len(pattern) > sha256.Size*2 || len(pattern)%2 != 0 {
+			return "", fmt.Errorf("hash prefix must be 4 to 32 bytes in hex")
+		}
+		if _, err := hex.DecodeString(pattern); err != nil {
+			return "", fmt.Errorf("hash prefix must be hex encoded")
+		}
+		return pattern, nil
+	default:
+		return "", fmt.Errorf("kind must be exact, wildcard, regex or hash_prefix")
+	}
+}
+
+// compiledDomainRules правила, подготовленные для быстрого сопоставления
+type compiledDomainRules struct {
+	block domainRuleSet
+	allow domainRuleSet
+}
+
+// domainRuleSet набор правил одного действия
+type domainRuleSet struct {
+	exact    map[string]*entity.DomainRule
+	wildcard map[string]*entity.DomainRule
+	regex    []compiledRegexRule
+	// hashPrefixes сгруппированы по длине префикса
+	hashPrefixes map[int]map[string]*entity.DomainRule
+}
+
+// compiledRegexRule правило с скомпилированным регулярным выражением
+type compiledRegexRule struct {
+	rule *entity.DomainRule
+	re   *regexp.Regexp
+}
+
+// compileDomainRules раскладывает правила по наборам; некорректные правила пропускаются
+func compileDomainRules(rules []*entity.DomainRule) *compiledDomainRules {
+	compiled := &compiledDomainRules{
+		block: newDomainRuleSet(),
+		allow: newDomainRuleSet(),
+	}
+
+	for _, rule := range rules {
+		set := &compiled.block
+		if rule.Action == entity.DomainRuleAllow {
+			set = &compiled.allow
+		}
+
+		switch rule.Kind {
+		case entity.DomainRuleExact:
+			set.exact[rule.Pattern] = rule
+		case entity.DomainRuleWildcard:
+			set.wildcard[strings.TrimPrefix(rule.Pattern, "*.")] = rule
+		case entity.DomainRuleRegex:
+			if re, err := regexp.Compile(rule.Pattern); err == nil {
+				set.regex = append(set.regex, compiledRegexRule{rule: rule, re: re})
+			}
+		case entity.DomainRuleHashPrefix:
+			prefixes, ok := set.hashPrefixes[len(rule.Pattern)]
+			if !ok {
+				prefixes = make(map[string]*entity.DomainRule)
+				set.hashPrefixes[len(rule.Pattern)] = prefixes
+			}
+			prefixes[rule.Pattern] = rule
+		}
+	}
+
+	return compiled
+}
+
+// newDomainRuleSet создаёт пустой набор правил
+func newDomainRuleSet() domainRuleSet {
+	return domainRuleSet{
+		exact:        make(map[string]*entity.DomainRule),
+		wildcard:     make(map[string]*entity.DomainRule),
+		hashPrefixes: make(map[int]map[string]*entity.DomainRule),
+	}
+}
+
+// match возвращает первое совпавшее правило или nil
+func (s *domainRuleSet) match(target *ruleTarget) *entity.DomainRule {
+	if rule, ok := s.exact[target.host]; ok {
+		return rule
+	}
+
+	// Для a.b.example.com проверяем b.example.com, example.com и com
+	for host := target.host; ; {
+		i := strings.IndexByte(host, '.')
+		if i < 0 {
+			break
+		}
+		host = host[i+1:]
+		if rule, ok := s.wildcard[host]; ok {
+			return rule
+		}
+	}
+
+	for _, r := range s.regex {
+		if r.re.MatchString(target.url) {
+			return r.rule
+		}
+	}
+
+	if len(s.hashPrefixes) > 0 {
+		for _, hash := range target.hashes() {
+			for length, prefixes := range s.hashPrefixes {
+				if rule, ok := prefixes[hash[:length]]; ok {
+					return rule
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ruleTarget проверяемый URL
+type ruleTarget struct {
+	url  string
+	host string
+	path string
+	// rawQuery без "?"
+	rawQuery string
+
+	hashList []string
+}
+
+// newRuleTarget разбирает канонический URL
+func newRuleTarget(canonicalURL string) (*ruleTarget, error) {
+	u, err := url.Parse(canonicalURL)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := service.NormalizeHost(u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	return &ruleTarget{
+		url:      canonicalURL,
+		host:     host,
+		path:     path,
+		rawQuery: u.RawQuery,
+	}, nil
+}
+
+// hashes возвращает SHA-256 (hex) выражений URL в стиле Safe Browsing:
+// до 5 вариантов хоста, умноженных на до 6 вариантов пути
+func (t *ruleTarget) hashes() []string {
+	if t.hashList != nil {
+		return t.hashList
+	}
+
+	for _, host := range t.hostSuffixes() {
+		for _, path := range t.pathPrefixes() {
+			sum := sha256.Sum256([]byte(host + path))
+			t.hashList = append(t.hashList, hex.EncodeToString(sum[:]))
+		}
+	}
+	return t.hashList
+}
+
+// hostSuffixes возвращает хост и до 4 его суффиксов, начиная с последних 5 компонентов
+func (t *ruleTarget) hostSuffixes() []string {
+	suffixes := []string{t.host}
+	if net.ParseIP(t.host) != nil {
+		return suffixes
+	}
+
+	components := strings.Split(t.host, ".")
+	start := len(components) - 5
+	if start < 1 {
+		start = 1
+	}
+	// Домен верхнего уровня отдельно не проверяется
+	for i := start; i <= len(components)-2; i++ {
+		suffixes = append(suffixes, strings.Join(components[i:], "."))
+	}
+	return suffixes
+}
+
+// pathPrefixes возвращает путь с query, путь без query, корень и до 3 префиксов пути
+func (t *ruleTarget) pathPrefixes() []string {
+	seen := make(map[string]struct{})
+	var prefixes []string
+	add := func(p string) {
+		if _, ok := seen[p]; !ok {
+			seen[p] = struct{}{}
+			prefixes = append(prefixes, p)
+		}
+	}
+
+	if t.rawQuery != "" {
+		add(t.path + "?" + t.rawQuery)
+	}
+	add(t.path)
+	add("/")
+
+	components := strings.Split(strings.Trim(t.path, "/"), "/")
+	prefix := "/"
+	for i := 0; i < len(components) && i < 3 && len(prefixes) < 6; i++ {
+		if components[i] == "" {
+			break
+		}
+		prefix += components[i] + "/"
+		add(prefix)
+	}
+
+	return prefixes
+}
@@ -7,6 +7,9 @@ var (
 	// ErrAliasExists возвращается когда кастомный алиас уже существует
 	ErrAliasExists = errors.New("custom alias already exists")
 
+	// ErrInvalidAlias возвращается когда кастомный алиас не проходит политику алиасов
+	ErrInvalidAlias = errors.New("invalid custom alias")
+
 	// ErrLinkNotFound возвращается когда ссылка не найдена
 	ErrLinkNotFound = errors.New("link not found")
 
@@ -15,4 +18,145 @@ var (
 
 	// ErrURLRequired возвращается когда URL не указан
 	ErrURLRequired = errors.New("original_url is required")
+
+	// ErrTargetNotAllowed возвращается когда URL указывает на запрещённый адрес (защита от SSRF)
+	ErrTargetNotAllowed = errors.New("target URL is not allowed")
+
+	// ErrDomainBlocked возвращается когда целевой адрес запрещён правилами доменов
+	ErrDomainBlocked = errors.New("target domain is blocked")
+
+	// ErrLinkBlocked возвращается при переходе по ссылке, цель которой заблокирована после создания
+	ErrLinkBlocked = errors.New("link target is blocked")
+
+	// ErrInvalidDomainRule возвращается когда правило домена задано неверно
+	ErrInvalidDomainRule = errors.New("invalid domain rule")
+
+	// ErrDomainRuleNotFound возвращается когда правило домена не найдено
+	ErrDomainRuleNotFound = errors.New("domain rule not found")
+
+	// ErrInvalidLinkPassword возвращается когда пароль для новой ссылки не подходит по длине
+	ErrInvalidLinkPassword = errors.New("invalid link password")
+
+	// ErrPasswordRequired возвращается при переходе по защищённой ссылке без подтверждённого пароля
+	ErrPasswordRequired = errors.New("link is password protected")
+
+	// ErrWrongPassword возвращается когда введён неверный пароль ссылки
+	ErrWrongPassword = errors.New("wrong link password")
+
+	// ErrSignatureRequired возвращается при переходе без подписи по ссылке, требующей подписи
+	ErrSignatureRequired = errors.New("link requires a signed URL")
+
+	// ErrInvalidSignature возвращается когда подпись URL неверна
+	ErrInvalidSignature = errors.New("invalid URL signature")
+
+	// ErrSignatureExpired возвращается когда срок действия подписанного URL истёк
+	ErrSignatureExpired = errors.New("signed URL has expired")
+
+	// ErrSigningDisabled возвращается когда ключи подписи не настроены
+	ErrSigningDisabled = errors.New("URL signing is not configured")
+
+	// ErrInvalidExpiry возвращается когда срок действия подписи задан неверно
+	ErrInvalidExpiry = errors.New("invalid signature expiry")
+
+	// ErrInvalidInterstitial возвращается когда задержка промежуточной страницы вне допустимого диапазона
+	ErrInvalidInterstitial = errors.New("invalid interstitial delay")
+
+	// ErrInvalidQROptions возвращается когда параметры QR-кода заданы неверно
+	ErrInvalidQROptions = errors.New("invalid QR code options")
+
+	// ErrInvalidTargetingRule возвращается когда правило таргетинга задано неверно
+	ErrInvalidTargetingRule = errors.New("invalid targeting rule")
+
+	// ErrInvalidGeoRule возвращается когда гео-правило задано неверно
+	ErrInvalidGeoRule = errors.New("invalid geo rule")
+
+	// ErrInvalidRoutingRule возвращается когда правило маршрутизации задано неверно
+	ErrInvalidRoutingRule = errors.New("invalid routing rule")
+
+	// ErrInvalidActiveWindow возвращается когда окно активности ссылки задано неверно
+	ErrInvalidActiveWindow = errors.New("invalid active window")
+
+	// ErrLinkExpired возвращается при переходе по ссылке, окно активности которой закрылось
+	ErrLinkExpired = errors.New("link has expired")
+
+	// ErrInvalidVariant возвращается когда варианты A/B-теста заданы неверно
+	ErrInvalidVariant = errors.New("invalid variant")
+
+	// ErrInvalidLinkUpdate возвращается когда изменение ссылки задано неверно
+	ErrInvalidLinkUpdate = errors.New("invalid link update")
+
+	// ErrRevisionConflict возвращается когда ссылку изменили после того, как клиент получил её ревизию
+	ErrRevisionConflict = errors.New("link was modified concurrently")
+
+	// ErrRevisionNotFound возвращается когда ревизия ссылки не найдена
+	ErrRevisionNotFound = errors.New("link revision not found")
+
+	// ErrInvalidTag возвращается когда тег задан неверно
+	ErrInvalidTag = errors.New("invalid tag")
+
+	// ErrTagNotFound возвращается когда тег не найден
+	ErrTagNotFound = errors.New("tag not found")
+
+	// ErrInvalidCampaign возвращается когда имя кампании задано неверно
+	ErrInvalidCampaign = errors.New("invalid campaign")
+
+	// ErrCampaignNotFound возвращается когда кампания не найдена
+	ErrCampaignNotFound = errors.New("campaign not found")
+
+	// ErrCampaignExists возвращается когда кампания с таким именем уже существует
+	ErrCampaignExists = errors.New("campaign already exists")
+
+	// ErrInvalidUTM возвращается когда UTM-метки или шаблон заданы неверно
+	ErrInvalidUTM = errors.New("invalid utm parameters")
+
+	// ErrUTMTemplateNotFound возвращается когда шаблон UTM-меток не найден
+	ErrUTMTemplateNotFound = errors.New("utm template not found")
+
+	// ErrUTMTemplateExists возвращается когда шаблон с таким именем уже существует
+	ErrUTMTemplateExists = errors.New("utm template already exists")
+
+	// ErrInvalidPage возвращается когда страница ссылок задана неверно
+	ErrInvalidPage = errors.New("invalid page")
+
+	// ErrPageNotFound возвращается когда страница ссылок не найдена
+	ErrPageNotFound = errors.New("page not found")
+
+	// ErrPageExists возвращается когда страница с таким адресом уже существует
+	ErrPageExists = errors.New("page already exists")
+
+	// ErrInvalidDeepLink возвращается когда адреса открытия приложения заданы неверно
+	ErrInvalidDeepLink = errors.New("invalid deep link")
+
+	// ErrInvalidOpenGraph возвращается когда поля превью для соцсетей заданы неверно
+	ErrInvalidOpenGraph = errors.New("invalid open graph")
+
+	// ErrOpenGraphFetchDisabled возвращается когда загрузка метаданных Open Graph отключена
+	ErrOpenGraphFetchDisabled = errors.New("open graph fetching is disabled")
+
+	// ErrOpenGraphFetchFailed возвращается когда не удалось загрузить метаданные с адреса назначения
+	ErrOpenGraphFetchFailed = errors.New("failed to fetch open graph")
+
+	// ErrHealthCheckDisabled возвращается когда проверка доступности ссылок отключена
+	ErrHealthCheckDisabled = errors.New("health check is disabled")
+
+	// ErrInvalidHealthSubscription возвращается когда подписка на уведомления задана неверно
+	ErrInvalidHealthSubscription = errors.New("invalid health subscription")
+
+	// ErrHealthSubscriptionNotFound возвращается когда подписка на уведомления не найдена
+	ErrHealthSubscriptionNotFound = errors.New("health subscription not found")
+
+	// ErrUnsupportedFormat возвращается когда формат импорта/экспорта не поддерживается
+	ErrUnsupportedFormat = errors.New("unsupported format")
+
+	// ErrInvalidImportFile возвращается когда файл импорта не удалось разобрать
+	ErrInvalidImportFile = errors.New("invalid import file")
+
+	// ErrImportJobNotFound возвращается когда задача импорта не найдена
+	ErrImportJobNotFound = errors.New("import job not found")
+
+	// ErrIdempotencyKeyReused возвращается когда ключ идемпотентности использован с другим запросом
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+
+	// ErrIdempotencyInProgress возвращается когда запрос с тем же ключом ещё выполняется
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is in progress")
 )
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// ExportRecord ссылка в файле экспорта
+type ExportRecord struct {
+	*entity.Link
+	ShortLink string `json:"short_link"`
+	// PasswordHash хэш пароля, чтобы защищённая ссылка после импорта осталась защищённой
+	PasswordHash string `json:"password_hash,omitempty"`
+}
+
+// exportColumns колонки CSV-выгрузки. Списки и вложенные структуры записываются в ячейку
+// как JSON, чтобы выгрузка загружалась обратно без потери настроек ссылки.
+var exportColumns = []string{
+	"id", "short_url", "short_link", "original_url", "canonical_url", "custom_alias", "owner",
+	"campaign", "tags", "protected", "password_hash", "require_signature", "interstitial_seconds",
+	"targeting_rules", "geo_rules", "rules", "variants", "active_from", "active_until",
+	"prelaunch_url", "open_graph", "deep_link", "created_at",
+}
+
+// ExportUseCase обрабатывает экспорт каталога ссылок, включая хэши паролей
+type ExportUseCase struct {
+	linkRepo         repository.LinkRepository
+	shortenerService *service.ShortenerService
+}
+
+// NewExportUseCase создаёт новый use case
+func NewExportUseCase(
+	linkRepo repository.LinkRepository,
+	shortenerService *service.ShortenerService,
+) *ExportUseCase {
+	return &ExportUseCase{
+		linkRepo:         linkRepo,
+		shortenerService: shortenerService,
+	}
+}
+
+// Execute выгружает все ссылки в w в указанном формате
+func (uc *ExportUseCase) Execute(ctx context.Context, format string, w io.Writer) error {
+	switch format {
+	case FormatCSV:
+		return uc.exportCSV(ctx, w)
+	case FormatJSON:
+		return uc.exportJSON(ctx, w)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// exportCSV выгружает ссылки в CSV с заголовком, совместимым с импортом
+func (uc *ExportUseCase) exportCSV(ctx context.Context, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportColumns); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	err := uc.forEachLink(ctx, func(link *entity.Link) error {
+		row, err := uc.csvRow(link)
+		if err != nil {
+			return err
+		}
+		return writer.Write(row)
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvRow возвращает строку CSV-выгрузки в порядке exportColumns
+func (uc *ExportUseCase) csvRow(link *entity.Link) ([]string, error) {
+	var marshalErr error
+	cell := func(value interface{}) string {
+		data, err := jsonCell(value)
+		if err != nil && marshalErr == nil {
+			marshalErr = err
+		}
+		return data
+	}
+
+	row := []string{
+		strconv.FormatInt(link.ID, 10),
+		link.ShortURL,
+		uc.shortenerService.BuildShortURL(link.ShortURL),
+		link.OriginalURL,
+		link.CanonicalURL,
+		link.CustomAlias,
+		link.Owner,
+		link.Campaign,
+		cell(link.Tags),
+		strconv.FormatBool(link.Protected),
+		link.PasswordHash,
+		strconv.FormatBool(link.RequireSignature),
+		strconv.Itoa(link.InterstitialSeconds),
+		cell(link.TargetingRules),
+		cell(link.GeoRules),
+		cell(link.Rules),
+		cell(link.Variants),
+		timeCell(link.ActiveFrom),
+		timeCell(link.ActiveUntil),
+		link.PrelaunchURL,
+		cell(link.OpenGraph),
+		cell(link.DeepLink),
+		link.CreatedAt.Format(time.RFC3339),
+	}
+	if marshalErr != nil {
+		return nil, fmt.Errorf("failed to marshal link %s: %w", link.ShortURL, marshalErr)
+	}
+	return row, nil
+}
+
+// jsonCell записывает значение в ячейку CSV как JSON; пустые значения дают пустую ячейку
+func jsonCell(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	if cell := string(data); cell != "null" && cell != "[]" {
+		return cell, nil
+	}
+	return "", nil
+}
+
+// timeCell записывает необязательное время в формате RFC 3339
+func timeCell(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// exportJSON выгружает ссылки JSON-массивом, не держа весь каталог в памяти
+func (uc *ExportUseCase) exportJSON(ctx context.Context, w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	first := true
+	err := uc.forEachLink(ctx, func(link *entity.Link) error {
+		data, err := json.Marshal(ExportRecord{
+			Link:         link,
+			ShortLink:    uc.shortenerService.BuildShortURL(link.ShortURL),
+			PasswordHash: link.PasswordHash,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal link: %w", err)
+		}
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
+
+// forEachLink обходит все ссылки постранично
+func (uc *ExportUseCase) forEachLink(ctx context.Context, fn func(link *entity.Link) error) error {
+	for offset := 0; ; offset += ExportBatchSize {
+		links, err := uc.linkRepo.List(ctx, repository.LinkFilter{Limit: ExportBatchSize, Offset: offset})
+		if err != nil {
+			return fmt.Errorf("failed to list links: %w", err)
+		}
+
+		for _, link := range links {
+			if err := fn(link); err != nil {
+				return err
+			}
+		}
+
+		if len(links) < ExportBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// continents коды континентов, допустимые в гео-правилах
+var continents = map[string]bool{
+	"AF": true, "AN": true, "AS": true, "EU": true, "NA": true, "OC": true, "SA": true,
+}
+
+// GeoLocation местоположение клиента
+type GeoLocation struct {
+	// Country код страны ISO 3166-1 alpha-2
+	Country string
+	// Continent двухбуквенный код континента
+	Continent string
+}
+
+// GeoResolver определяет местоположение по IP-адресу
+type GeoResolver interface {
+	Lookup(ipAddress string) (GeoLocation, bool)
+}
+
+// checkGeoRules проверяет гео-правила и приводит коды к верхнему регистру
+func (uc *ShortenUseCase) checkGeoRules(ctx context.Context, rules []entity.GeoRule) error {
+	if len(rules) > MaxGeoRules {
+		return fmt.Errorf("%w: at most %d rules are allowed", ErrInvalidGeoRule, MaxGeoRules)
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if len(rule.Countries) == 0 && len(rule.Continents) == 0 {
+			return fmt.Errorf("%w: rule %d has no countries or continents", ErrInvalidGeoRule, i)
+		}
+		for j, country := range rule.Countries {
+			country = strings.ToUpper(country)
+			if len(country) != 2 || country[0] < 'A' || country[0] > 'Z' || country[1] < 'A' || country[1] > 'Z' {
+				return fmt.Errorf("%w: rule %d: invalid country code %q", ErrInvalidGeoRule, i, rule.Countries[j])
+			}
+			rule.Countries[j] = country
+		}
+		for j, continent := range rule.Continents {
+			continent = strings.ToUpper(continent)
+			if !continents[continent] {
+				return fmt.Errorf("%w: rule %d: invalid continent code %q", ErrInvalidGeoRule, i, rule.Continents[j])
+			}
+			rule.Continents[j] = continent
+		}
+
+		if err := uc.checkRuleURL(ctx, rule.URL); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// matchGeo возвращает URL первого гео-правила, подходящего под местоположение, и его идентификатор
+func matchGeo(rules []entity.GeoRule, location GeoLocation) (string, string, bool) {
+	for i, rule := range rules {
+		if containsString(rule.Countries, location.Country) || containsString(rule.Continents, location.Continent) {
+			return rule.URL, "geo:" + strconv.Itoa(i), true
+		}
+	}
+	return "", "", false
+}
+
+// containsString проверяет вхождение непустой строки в список
+func containsString(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// HealthProber проверяет доступность адреса: выполняет запрос, проходит по редиректам
+// и возвращает результат. Ошибки сети и ответа записываются в результат.
+type HealthProber interface {
+	Probe(ctx context.Context, rawURL string) entity.HealthCheck
+}
+
+// HealthNotifier доставляет уведомление о смене доступности адреса подписчику
+type HealthNotifier interface {
+	Notify(ctx context.Context, subscription *entity.HealthSubscription, alert entity.HealthAlert) error
+}
+
+// HealthUseCase периодически проверяет адреса ссылок, хранит историю проверок
+// и уведомляет подписчиков, когда адрес перестаёт отвечать или снова доступен
+type HealthUseCase struct {
+	linkRepo         repository.LinkRepository
+	healthRepo       repository.HealthRepository
+	subscriptionRepo repository.HealthSubscriptionRepository
+	prober           HealthProber
+	notifier         HealthNotifier
+	urlPolicy        *service.URLPolicy
+	interval         time.Duration
+	concurrency      int
+}
+
+// NewHealthUseCase создаёт новый use case. Каждая ссылка проверяется не чаще раза
+// в interval, одновременно выполняется не больше concurrency проверок.
+// Если prober равен nil, фоновые и ручные проверки отключены.
+func NewHealthUseCase(
+	linkRepo repository.LinkRepository,
+	healthRepo repository.HealthRepository,
+	subscriptionRepo repository.HealthSubscriptionRepository,
+	prober HealthProber,
+	notifier HealthNotifier,
+	urlPolicy *service.URLPolicy,
+	interval time.Duration,
+	concurrency int,
+) *HealthUseCase {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &HealthUseCase{
+		linkRepo:         linkRepo,
+		healthRepo:       healthRepo,
+		subscriptionRepo: subscriptionRepo,
+		prober:           prober,
+		notifier:         notifier,
+		urlPolicy:        urlPolicy,
+		interval:         interval,
+		concurrency:      concurrency,
+	}
+}
+
+// LinkHealthReport состояние доступности ссылки с историей проверок
+type LinkHealthReport struct {
+	ShortURL    string                `json:"short_url"`
+	OriginalURL string                `json:"original_url"`
+	Health      *entity.LinkHealth    `json:"health"`
+	Checks      []*entity.HealthCheck `json:"checks"`
+}
+
+// SubscribeRequest запрос на подписку на уведомления о доступности
+type SubscribeRequest struct {
+	Owner      string `json:"owner,omitempty"`
+	WebhookURL string `json:"webhook_url"`
+	Secret     string `json:"secret,omitempty"`
+}
+
+// Run проверяет ссылки, пока не отменён ctx. Ссылки, срок проверки которых подошёл,
+// выбираются раз в HealthCheckTick.
+func (uc *HealthUseCase) Run(ctx context.Context) {
+	if uc.prober == nil {
+		return
+	}
+
+	ticker := time.NewTicker(HealthCheckTick)
+	defer ticker.Stop()
+
+	for {
+		if _, err := uc.CheckDue(ctx); err != nil {
+			// Ошибка БД не останавливает проверки, следующая попытка — на следующем тике
+			_ = err
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckDue проверяет все ссылки, срок проверки которых подошёл, и возвращает их число
+func (uc *HealthUseCase) CheckDue(ctx context.Context) (int, error) {
+	if uc.prober == nil {
+		return 0, ErrHealthCheckDisabled
+	}
+
+	checked := 0
+	for ctx.Err() == nil {
+		links, err := uc.healthRepo.ListDue(ctx, time.Now().Add(-uc.interval), HealthCheckBatchSize)
+		if err != nil {
+			return checked, fmt.Errorf("failed to list links for health check: %w", err)
+		}
+		if len(links) == 0 {
+			break
+		}
+
+		// Ограничиваем число одновременных проверок; вежливость к хостам обеспечивает prober
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, uc.concurrency)
+		for _, link := range links {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(link *entity.Link) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if _, err := uc.check(ctx, link); err != nil {
+					// Ссылка будет выбрана снова на следующем проходе
+					_ = err
+				}
+			}(link)
+		}
+		wg.Wait()
+
+		checked += len(links)
+		if len(links) < HealthCheckBatchSize {
+			break
+		}
+	}
+
+	return checked, nil
+}
+
+// Check сразу проверяет адрес ссылки и возвращает обновлённое состояние
+func (uc *HealthUseCase) Check(ctx context.Context, shortURL string) (*LinkHealthReport, error) {
+	if uc.prober == nil {
+		return nil, ErrHealthCheckDisabled
+	}
+
+	link, err := uc.getLink(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.check(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return uc.report(ctx, link)
+}
+
+// Health возвращает состояние доступности ссылки и последние проверки
+func (uc *HealthUseCase) Health(ctx context.Context, shortURL string) (*LinkHealthReport, error) {
+	link, err := uc.getLink(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	return uc.report(ctx, link)
+}
+
+// Subscribe создаёт подписку на уведомления о доступности ссылок владельца.
+// Пустой владелец означает подписку на все ссылки.
+func (uc *HealthUseCase) Subscribe(ctx context.Context, req SubscribeRequest) (*entity.HealthSubscription, error) {
+	webhookURL := strings.TrimSpace(req.WebhookURL)
+	if err := ValidateURL(webhookURL); err != nil {
+		return nil, fmt.Errorf("%w: webhook_url: %v", ErrInvalidHealthSubscription, err)
+	}
+	// Уведомления отправляет сервер, поэтому адрес проверяется так же, как адреса ссылок
+	if uc.urlPolicy != nil {
+		if err := uc.urlPolicy.Check(ctx, webhookURL); err != nil {
+			return nil, fmt.Errorf("%w: webhook_url: %v", ErrInvalidHealthSubscription, err)
+		}
+	}
+
+	subscription := &entity.HealthSubscription{
+		Owner:      strings.TrimSpace(req.Owner),
+		WebhookURL: webhookURL,
+		Secret:     req.Secret,
+		CreatedAt:  time.Now(),
+	}
+	if err := uc.subscriptionRepo.Create(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create health subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// ListSubscriptions возвращает все подписки
+func (uc *HealthUseCase) ListSubscriptions(ctx context.Context) ([]*entity.HealthSubscription, error) {
+	subscriptions, err := uc.subscriptionRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list health subscriptions: %w", err)
+	}
+	if subscriptions == nil {
+		subscriptions = []*entity.HealthSubscription{}
+	}
+	return subscriptions, nil
+}
+
+// Unsubscribe удаляет подписку
+func (uc *HealthUseCase) Unsubscribe(ctx context.Context, id int64) error {
+	deleted, err := uc.subscriptionRepo.Delete(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete health subscription: %w", err)
+	}
+	if !deleted {
+		return ErrHealthSubscriptionNotFound
+	}
+	return nil
+}
+
+// check проверяет адрес ссылки, сохраняет результат и рассылает уведомления о смене состояния
+func (uc *HealthUseCase) check(ctx context.Context, link *entity.Link) (*entity.LinkHealth, error) {
+	check := uc.prober.Probe(ctx, link.OriginalURL)
+	check.LinkID = link.ID
+	if check.CheckedAt.IsZero() {
+		check.CheckedAt = time.Now()
+	}
+	// Проверка прервана остановкой сервера, а не недоступностью адреса
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	health := nextHealth(link.Health, check)
+	if err := uc.healthRepo.SaveCheck(ctx, &check, &health, MaxHealthHistory); err != nil {
+		return nil, fmt.Errorf("failed to save health check: %w", err)
+	}
+
+	if event := healthEvent(link.Health, health); event != "" {
+		uc.notify(ctx, link, entity.HealthAlert{
+			Event:       event,
+			ShortURL:    link.ShortURL,
+			OriginalURL: link.OriginalURL,
+			Owner:       link.Owner,
+			Health:      health,
+			Check:       check,
+		})
+	}
+
+	link.Health = &health
+	return &health, nil
+}
+
+// notify отправляет уведомление подписчикам владельца ссылки; ошибка доставки
+// не влияет на сохранённое состояние
+func (uc *HealthUseCase) notify(ctx context.Context, link *entity.Link, alert entity.HealthAlert) {
+	if uc.notifier == nil || uc.subscriptionRepo == nil {
+		return
+	}
+
+	subscriptions, err := uc.subscriptionRepo.ListForOwner(ctx, link.Owner)
+	if err != nil {
+		return
+	}
+	for _, subscription := range subscriptions {
+		if err := uc.notifier.Notify(ctx, subscription, alert); err != nil {
+			// Уведомления не повторяются: следующее придёт при следующей смене состояния
+			_ = err
+		}
+	}
+}
+
+// report собирает состояние ссылки и историю проверок
+func (uc *HealthUseCase) report(ctx context.Context, link *entity.Link) (*LinkHealthReport, error) {
+	checks, err := uc.healthRepo.ListChecks(ctx, link.ID, MaxHealthHistory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list health checks: %w", err)
+	}
+	if checks == nil {
+		checks = []*entity.HealthCheck{}
+	}
+
+	return &LinkHealthReport{
+		ShortURL:    link.ShortURL,
+		OriginalURL: link.OriginalURL,
+		Health:      link.Health,
+		Checks:      checks,
+	}, nil
+}
+
+// getLink получает ссылку из БД
+func (uc *HealthUseCase) getLink(ctx context.Context, shortURL string) (*entity.Link, error) {
+	link, err := uc.linkRepo.GetByShortURL(ctx, shortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+	return link, nil
+}
+
+// nextHealth вычисляет состояние ссылки после проверки. Адрес считается недоступным
+// только после HealthFailureThreshold неудачных проверок подряд, чтобы разовый сбой
+// не вызывал уведомление.
+func nextHealth(prev *entity.LinkHealth, check entity.HealthCheck) entity.LinkHealth {
+	health := entity.LinkHealth{
+		Status:     entity.HealthHealthy,
+		StatusCode: check.StatusCode,
+		Error:      check.Error,
+		CheckedAt:  check.CheckedAt,
+		ChangedAt:  check.CheckedAt,
+	}
+
+	if !check.Healthy {
+		health.ConsecutiveFailures = 1
+		if prev != nil {
+			health.ConsecutiveFailures = prev.ConsecutiveFailures + 1
+		}
+		health.Status = entity.HealthFailing
+		if health.ConsecutiveFailures >= HealthFailureThreshold {
+			health.Status = entity.HealthBroken
+		}
+	}
+
+	if prev != nil && prev.Status == health.Status {
+		health.ChangedAt = prev.ChangedAt
+	}
+	return health
+}
+
+// healthEvent возвращает событие уведомления при смене состояния; пусто — уведомлять не нужно
+func healthEvent(prev *entity.LinkHealth, next entity.LinkHealth) string {
+	wasBroken := prev != nil && prev.Status == entity.HealthBroken
+	switch {
+	case next.Status == entity.HealthBroken && !wasBroken:
+		return entity.HealthEventBroken
+	case next.Status == entity.HealthHealthy && wasBroken:
+		return entity.HealthEventRecovered
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// fakeHealthLinkRepo возвращает одну ссылку по короткому коду
+type fakeHealthLinkRepo struct {
+	repository.LinkRepository
+	link *entity.Link
+}
+
+func (r *fakeHealthLinkRepo) GetByShortURL(ctx context.Context, shortURL string) (*entity.Link, error) {
+	if r.link.ShortURL != shortURL {
+		return nil, nil
+	}
+	return r.link, nil
+}
+
+// fakeHealthRepo хранит проверки в памяти
+type fakeHealthRepo struct {
+	checks []*entity.HealthCheck
+}
+
+func (r *fakeHealthRepo) ListDue(ctx context.Context, before time.Time, limit int) ([]*entity.Link, error) {
+	return nil, nil
+}
+
+func (r *fakeHealthRepo) SaveCheck(ctx context.Context, check *entity.HealthCheck, health *entity.LinkHealth, keep int) error {
+	r.checks = append([]*entity.HealthCheck{check}, r.checks...)
+	return nil
+}
+
+func (r *fakeHealthRepo) ListChecks(ctx context.Context, linkID int64, limit int) ([]*entity.HealthCheck, error) {
+	return r.checks, nil
+}
+
+// fakeSubscriptionRepo возвращает подписки владельца и подписки на все ссылки
+type fakeSubscriptionRepo struct {
+	repository.HealthSubscriptionRepository
+	subscriptions []*entity.HealthSubscription
+}
+
+func (r *fakeSubscriptionRepo) ListForOwner(ctx context.Context, owner string) ([]*entity.HealthSubscription, error) {
+	var result []*entity.HealthSubscription
+	for _, subscription := range r.subscriptions {
+		if subscription.Owner == "" || subscription.Owner == owner {
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+// fakeProber возвращает заданный результат проверки
+type fakeProber struct {
+	healthy bool
+}
+
+func (p *fakeProber) Probe(ctx context.Context, rawURL string) entity.HealthCheck {
+	if p.healthy {
+		return entity.HealthCheck{Healthy: true, StatusCode: 200}
+	}
+	return entity.HealthCheck{StatusCode: 503, Error: "service unavailable"}
+}
+
+// delivery уведомление, отправленное подписчику
+type delivery struct {
+	webhookURL string
+	alert      entity.HealthAlert
+}
+
+// fakeNotifier запоминает уведомления; err возвращается при каждой доставке
+type fakeNotifier struct {
+	mu         sync.Mutex
+	deliveries []delivery
+	err        error
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, subscription *entity.HealthSubscription, alert entity.HealthAlert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.deliveries = append(n.deliveries, delivery{webhookURL: subscription.WebhookURL, alert: alert})
+	return n.err
+}
+
+func (n *fakeNotifier) take() []delivery {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	deliveries := n.deliveries
+	n.deliveries = nil
+	return deliveries
+}
+
+func TestHealthCheckDeliversAlerts(t *testing.T) {
+	link := &entity.Link{ID: 1, ShortURL: "abc123", OriginalURL: "https://example.com", Owner: "alice"}
+	subscriptions := &fakeSubscriptionRepo{subscriptions: []*entity.HealthSubscription{
+		{ID: 1, Owner: "alice", WebhookURL: "https://hooks.example/alice"},
+		{ID: 2, WebhookURL: "https://hooks.example/all"},
+		{ID: 3, Owner: "bob", WebhookURL: "https://hooks.example/bob"},
+	}}
+	prober := &fakeProber{}
+	notifier := &fakeNotifier{}
+	uc := NewHealthUseCase(&fakeHealthLinkRepo{link: link}, &fakeHealthRepo{}, subscriptions, prober, notifier, nil, time.Hour, 1)
+	ctx := context.Background()
+
+	steps := []struct {
+		healthy    bool
+		wantStatus string
+		wantEvent  string
+	}{
+		// Первая неудача не достигает порога и не рассылается
+		{false, entity.HealthFailing, ""},
+		{false, entity.HealthBroken, entity.HealthEventBroken},
+		// Повторные неудачи не дублируют уведомление
+		{false, entity.HealthBroken, ""},
+		{true, entity.HealthHealthy, entity.HealthEventRecovered},
+		{true, entity.HealthHealthy, ""},
+	}
+
+	for i, step := range steps {
+		prober.healthy = step.healthy
+		report, err := uc.Check(ctx, "abc123")
+		if err != nil {
+			t.Fatalf("step %d: Check = %v", i, err)
+		}
+		if report.Health.Status != step.wantStatus {
+			t.Fatalf("step %d: status = %q, want %q", i, report.Health.Status, step.wantStatus)
+		}
+
+		deliveries := notifier.take()
+		if step.wantEvent == "" {
+			if len(deliveries) != 0 {
+				t.Fatalf("step %d: got %d deliveries, want none", i, len(deliveries))
+			}
+			continue
+		}
+		if len(deliveries) != 2 {
+			t.Fatalf("step %d: got %d deliveries, want 2 (owner and catch-all)", i, len(deliveries))
+		}
+		for _, d := range deliveries {
+			if d.webhookURL == "https://hooks.example/bob" {
+				t.Fatalf("step %d: alert delivered to another owner's subscription", i)
+			}
+			if d.alert.Event != step.wantEvent || d.alert.ShortURL != "abc123" || d.alert.Owner != "alice" {
+				t.Fatalf("step %d: alert = %+v, want event %q", i, d.alert, step.wantEvent)
+			}
+		}
+	}
+}
+
+func TestHealthCheckIgnoresDeliveryErrors(t *testing.T) {
+	link := &entity.Link{ID: 1, ShortURL: "abc123", OriginalURL: "https://example.com",
+		Health: &entity.LinkHealth{Status: entity.HealthFailing, ConsecutiveFailures: 1}}
+	subscriptions := &fakeSubscriptionRepo{subscriptions: []*entity.HealthSubscription{
+		{ID: 1, WebhookURL: "https://hooks.example/all"},
+	}}
+	notifier := &fakeNotifier{err: errors.New("connection refused")}
+	healthRepo := &fakeHealthRepo{}
+	uc := NewHealthUseCase(&fakeHealthLinkRepo{link: link}, healthRepo, subscriptions, &fakeProber{}, notifier, nil, time.Hour, 1)
+
+	report, err := uc.Check(context.Background(), "abc123")
+	if err != nil {
+		t.Fatalf("Check = %v", err)
+	}
+	if report.Health.Status != entity.HealthBroken || len(healthRepo.checks) != 1 {
+		t.Fatalf("report = %+v, want broken status with saved check", report)
+	}
+	if len(notifier.take()) != 1 {
+		t.Fatal("alert was not attempted")
+	}
+}
+
+func TestHealthCheckDisabled(t *testing.T) {
+	uc := NewHealthUseCase(nil, nil, nil, nil, nil, nil, time.Hour, 1)
+	if _, err := uc.Check(context.Background(), "abc123"); !errors.Is(err, ErrHealthCheckDisabled) {
+		t.Fatalf("Check = %v, want ErrHealthCheckDisabled", err)
+	}
+}
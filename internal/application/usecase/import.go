@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+const (
+	// FormatCSV формат CSV для импорта и экспорта
+	FormatCSV = "csv"
+	// FormatJSON формат JSON для импорта и экспорта
+	FormatJSON = "json"
+)
+
+// ImportRecord строка файла импорта
+type ImportRecord struct {
+	CreateLinkRequest
+	// ShortURL код ссылки из другого окружения, используется как алиас,
+	// если custom_alias не указан, чтобы сохранить коды при миграции
+	ShortURL string `json:"short_url,omitempty"`
+	// Protected и PasswordHash переносят защиту паролем из выгрузки
+	Protected    bool   `json:"protected,omitempty"`
+	PasswordHash string `json:"password_hash,omitempty"`
+}
+
+// toRequest преобразует строку импорта в запрос на создание ссылки. Защищённая ссылка
+// без хэша и без нового пароля отклоняется, чтобы она не стала публичной.
+func (r ImportRecord) toRequest() (CreateLinkRequest, error) {
+	req := r.CreateLinkRequest
+	if req.CustomAlias == "" && r.ShortURL != "" {
+		req.CustomAlias = r.ShortURL
+	}
+	// Новый пароль заменяет перенесённый хэш
+	if req.Password == "" {
+		req.passwordHash = r.PasswordHash
+	}
+	if r.Protected && req.Password == "" && req.passwordHash == "" {
+		return req, fmt.Errorf("%w: link is password protected, provide password_hash or password", ErrInvalidLinkPassword)
+	}
+	return req, nil
+}
+
+// ImportUseCase обрабатывает импорт каталогов ссылок
+type ImportUseCase struct {
+	shortenUseCase *ShortenUseCase
+
+	mu   sync.RWMutex
+	jobs map[string]*entity.ImportJob
+}
+
+// NewImportUseCase создаёт новый use case
+func NewImportUseCase(shortenUseCase *ShortenUseCase) *ImportUseCase {
+	return &ImportUseCase{
+		shortenUseCase: shortenUseCase,
+		jobs:           make(map[string]*entity.ImportJob),
+	}
+}
+
+// Parse разбирает файл импорта в формате CSV или JSON
+func (uc *ImportUseCase) Parse(format string, r io.Reader) ([]ImportRecord, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSVImport(r)
+	case FormatJSON:
+		var records []ImportRecord
+		if err := json.NewDecoder(r).Decode(&records); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		return records, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Execute синхронно импортирует ссылки и возвращает отчёт
+func (uc *ImportUseCase) Execute(ctx context.Context, format string, records []ImportRecord, dryRun bool) (*entity.ImportJob, error) {
+	job, err := newImportJob(format, len(records), dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.run(ctx, job, records)
+	return job, nil
+}
+
+// Start запускает импорт в фоне и возвращает задачу для отслеживания прогресса
+func (uc *ImportUseCase) Start(format string, records []ImportRecord, dryRun bool) (*entity.ImportJob, error) {
+	job, err := newImportJob(format, len(records), dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	uc.purgeExpiredLocked(time.Now())
+	uc.jobs[job.ID] = job
+	snapshot := snapshotImportJob(job)
+	uc.mu.Unlock()
+
+	// Контекст запроса завершится раньше задачи, поэтому используем фоновый
+	go uc.run(context.Background(), job, records)
+
+	return snapshot, nil
+}
+
+// GetJob возвращает текущее состояние задачи импорта
+func (uc *ImportUseCase) GetJob(id string) (*entity.ImportJob, error) {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+
+	job, ok := uc.jobs[id]
+	if !ok {
+		return nil, ErrImportJobNotFound
+	}
+	return snapshotImportJob(job), nil
+}
+
+// run обрабатывает строки импорта, обновляя прогресс задачи
+func (uc *ImportUseCase) run(ctx context.Context, job *entity.ImportJob, records []ImportRecord) {
+	uc.mu.Lock()
+	job.Status = entity.ImportStatusRunning
+	uc.mu.Unlock()
+
+	// Алиасы, уже встреченные в файле, чтобы находить дубликаты при dry-run
+	seen := make(map[string]int)
+
+	for i, record := range records {
+		row := i + 1
+		req, err := record.toRequest()
+		if err == nil {
+			err = uc.importRow(ctx, req, job.DryRun)
+		}
+		if err == nil && req.CustomAlias != "" {
+			if prev, ok := seen[req.CustomAlias]; ok {
+				err = fmt.Errorf("%w: duplicates row %d", ErrAliasExists, prev)
+			} else {
+				seen[req.CustomAlias] = row
+			}
+		}
+
+		uc.mu.Lock()
+		job.Processed++
+		if err != nil {
+			job.Failed++
+			job.Errors = append(job.Errors, entity.ImportRowError{
+				Row:         row,
+				OriginalURL: req.OriginalURL,
+				CustomAlias: req.CustomAlias,
+				Error:       err.Error(),
+			})
+		} else {
+			job.Succeeded++
+		}
+		uc.mu.Unlock()
+
+		if ctx.Err() != nil {
+			uc.finish(job, ctx.Err())
+			return
+		}
+	}
+
+	uc.finish(job, nil)
+}
+
+// importRow валидирует строку и, если это не dry-run, создаёт ссылку
+func (uc *ImportUseCase) importRow(ctx context.Context, req CreateLinkRequest, dryRun bool) error {
+	if err := uc.shortenUseCase.Validate(ctx, req); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	_, err := uc.shortenUseCase.Execute(ctx, req)
+	return err
+}
+
+// finish помечает задачу завершённой
+func (uc *ImportUseCase) finish(job *entity.ImportJob, err error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = entity.ImportStatusCompleted
+	if err != nil {
+		job.Status = entity.ImportStatusFailed
+		job.Error = err.Error()
+	}
+}
+
+// purgeExpiredLocked удаляет старые завершённые задачи, вызывается под блокировкой
+func (uc *ImportUseCase) purgeExpiredLocked(now time.Time) {
+	for id, job := range uc.jobs {
+		if job.FinishedAt != nil && now.Sub(*job.FinishedAt) > ImportJobRetention {
+			delete(uc.jobs, id)
+		}
+	}
+}
+
+// newImportJob создаёт задачу импорта со случайным идентификатором
+func newImportJob(format string, total int, dryRun bool) (*entity.ImportJob, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	return &entity.ImportJob{
+		ID:        hex.EncodeToString(id),
+		Status:    entity.ImportStatusPending,
+		Format:    format,
+		DryRun:    dryRun,
+		Total:     total,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// snapshotImportJob возвращает копию задачи, безопасную для чтения вне блокировки
+func snapshotImportJob(job *entity.ImportJob) *entity.ImportJob {
+	snapshot := *job
+	snapshot.Errors = append([]entity.ImportRowError(nil), job.Errors...)
+	return &snapshot
+}
+
+// parseCSVImport разбирает CSV с заголовком; колонки сопоставляются по имени.
+// Списки и вложенные структуры в ячейках записываются как JSON, как в выгрузке.
+func parseCSVImport(r io.Reader) ([]ImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read header: %v", ErrInvalidImportFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["original_url"]; !ok {
+		return nil, fmt.Errorf("%w: original_url column is required", ErrInvalidImportFile)
+	}
+
+	var records []ImportRecord
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+
+		record, err := parseCSVRecord(func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImportFile, line, err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// parseCSVRecord собирает строку импорта из значений колонок
+func parseCSVRecord(field func(name string) string) (ImportRecord, error) {
+	record := ImportRecord{
+		CreateLinkRequest: CreateLinkRequest{
+			OriginalURL:  field("original_url"),
+			CustomAlias:  field("custom_alias"),
+			Owner:        field("owner"),
+			Password:     field("password"),
+			PrelaunchURL: field("prelaunch_url"),
+			Campaign:     field("campaign"),
+		},
+		ShortURL:     field("short_url"),
+		PasswordHash: field("password_hash"),
+	}
+	req := &record.CreateLinkRequest
+
+	var err error
+	if record.Protected, err = boolCell(field("protected")); err != nil {
+		return record, fmt.Errorf("protected: %w", err)
+	}
+	if req.RequireSignature, err = boolCell(field("require_signature")); err != nil {
+		return record, fmt.Errorf("require_signature: %w", err)
+	}
+	if value := field("interstitial_seconds"); value != "" {
+		if req.InterstitialSeconds, err = strconv.Atoi(value); err != nil {
+			return record, fmt.Errorf("interstitial_seconds: %w", err)
+		}
+	}
+	if req.ActiveFrom, err = timeCellValue(field("active_from")); err != nil {
+		return record, fmt.Errorf("active_from: %w", err)
+	}
+	if req.ActiveUntil, err = timeCellValue(field("active_until")); err != nil {
+		return record, fmt.Errorf("active_until: %w", err)
+	}
+
+	structured := map[string]interface{}{
+		"tags":            &req.Tags,
+		"targeting_rules": &req.TargetingRules,
+		"geo_rules":       &req.GeoRules,
+		"rules":           &req.Rules,
+		"variants":        &req.Variants,
+		"open_graph":      &req.OpenGraph,
+		"deep_link":       &req.DeepLink,
+	}
+	for name, dest := range structured {
+		value := field(name)
+		if value == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(value), dest); err != nil {
+			return record, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return record, nil
+}
+
+// boolCell разбирает логическое значение ячейки; пустая ячейка означает false
+func boolCell(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// timeCellValue разбирает необязательное время в формате RFC 3339
+func timeCellValue(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
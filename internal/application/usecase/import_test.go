@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// fakeLinkRepo хранит ссылки в памяти по короткому коду
+type fakeLinkRepo struct {
+	repository.LinkRepository
+	links map[string]*entity.Link
+}
+
+func (r *fakeLinkRepo) Create(ctx context.Context, link *entity.Link) error {
+	if r.links == nil {
+		r.links = make(map[string]*entity.Link)
+	}
+	link.ID = int64(len(r.links) + 1)
+	r.links[link.ShortURL] = link
+	return nil
+}
+
+func (r *fakeLinkRepo) GetByShortURL(ctx context.Context, shortURL string) (*entity.Link, error) {
+	return r.links[shortURL], nil
+}
+
+func (r *fakeLinkRepo) GetByCustomAlias(ctx context.Context, alias string) (*entity.Link, error) {
+	for _, link := range r.links {
+		if link.CustomAlias == alias {
+			return link, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeLinkRepo) Exists(ctx context.Context, shortURL string) (bool, error) {
+	return r.links[shortURL] != nil, nil
+}
+
+func (r *fakeLinkRepo) List(ctx context.Context, filter repository.LinkFilter) ([]*entity.Link, error) {
+	var links []*entity.Link
+	for _, link := range r.links {
+		links = append(links, link)
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].ID < links[j].ID })
+	if filter.Offset >= len(links) {
+		return nil, nil
+	}
+	return links[filter.Offset:], nil
+}
+
+// newTestShortenUseCase создаёт use case создания ссылок поверх репозитория в памяти
+func newTestShortenUseCase(repo repository.LinkRepository) *ShortenUseCase {
+	return NewShortenUseCase(repo, service.NewShortenerService("https://sho.rt"), nil, nil,
+		service.NewURLNormalizer(false, nil), nil, nil, nil, nil, nil)
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret-pass"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	activeUntil := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	source := &fakeLinkRepo{}
+	for _, link := range []*entity.Link{
+		{ShortURL: "locked", CustomAlias: "locked", OriginalURL: "https://example.com/private",
+			Protected: true, PasswordHash: string(hash), Tags: []string{"team"}, Campaign: "spring"},
+		{ShortURL: "signed", CustomAlias: "signed", OriginalURL: "https://example.com/signed",
+			RequireSignature: true, ActiveUntil: &activeUntil, Rules: []entity.RoutingRule{{
+				When: entity.Condition{Language: []string{"ru"}},
+				URL:  "https://example.com/ru",
+			}}},
+		{ShortURL: "plain", CustomAlias: "plain", OriginalURL: "https://example.com/"},
+	} {
+		_ = source.Create(context.Background(), link)
+	}
+
+	for _, format := range []string{FormatCSV, FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			export := NewExportUseCase(source, service.NewShortenerService("https://sho.rt"))
+			if err := export.Execute(context.Background(), format, &buf); err != nil {
+				t.Fatalf("export = %v", err)
+			}
+
+			target := &fakeLinkRepo{}
+			importer := NewImportUseCase(newTestShortenUseCase(target))
+			records, err := importer.Parse(format, &buf)
+			if err != nil {
+				t.Fatalf("Parse = %v", err)
+			}
+			job, err := importer.Execute(context.Background(), format, records, false)
+			if err != nil || job.Failed != 0 {
+				t.Fatalf("import = %+v, %v", job, err)
+			}
+
+			for shortURL, want := range source.links {
+				got := target.links[shortURL]
+				if got == nil {
+					t.Fatalf("link %s was not imported", shortURL)
+				}
+				if got.OriginalURL != want.OriginalURL || got.Protected != want.Protected ||
+					got.PasswordHash != want.PasswordHash || got.RequireSignature != want.RequireSignature ||
+					got.Campaign != want.Campaign || strings.Join(got.Tags, ",") != strings.Join(want.Tags, ",") ||
+					!reflect.DeepEqual(got.Rules, want.Rules) || !sameTime(got.ActiveUntil, want.ActiveUntil) {
+					t.Fatalf("link %s = %+v, want %+v", shortURL, got, want)
+				}
+			}
+			// Перенесённый хэш принимает исходный пароль
+			if err := bcrypt.CompareHashAndPassword([]byte(target.links["locked"].PasswordHash), []byte("s3cret-pass")); err != nil {
+				t.Fatalf("imported password does not match: %v", err)
+			}
+		})
+	}
+}
+
+// sameTime сравнивает необязательные моменты времени
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestImportRejectsProtectedWithoutPassword(t *testing.T) {
+	target := &fakeLinkRepo{}
+	importer := NewImportUseCase(newTestShortenUseCase(target))
+	records := []ImportRecord{
+		{CreateLinkRequest: CreateLinkRequest{OriginalURL: "https://example.com/private"}, ShortURL: "locked", Protected: true},
+		{CreateLinkRequest: CreateLinkRequest{OriginalURL: "https://example.com/bad"}, ShortURL: "badhash", PasswordHash: "plain-text"},
+		{CreateLinkRequest: CreateLinkRequest{OriginalURL: "https://example.com/new", Password: "new-password"}, ShortURL: "renewed", Protected: true},
+	}
+
+	job, err := importer.Execute(context.Background(), FormatJSON, records, false)
+	if err != nil {
+		t.Fatalf("Execute = %v", err)
+	}
+	if job.Failed != 2 || len(target.links) != 1 || !target.links["renewed"].Protected {
+		t.Fatalf("job = %+v, links = %v; want two rejected rows and a protected renewed link", job, target.links)
+	}
+	for _, rowErr := range job.Errors {
+		if rowErr.CustomAlias == "" {
+			t.Fatalf("row error without alias: %+v", rowErr)
+		}
+	}
+	if _, err := (ImportRecord{Protected: true}).toRequest(); !errors.Is(err, ErrInvalidLinkPassword) {
+		t.Fatalf("toRequest = %v, want ErrInvalidLinkPassword", err)
+	}
+}
@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// LinkEditUseCase изменяет ссылки с сохранением истории и откатывает их к прошлым ревизиям
+type LinkEditUseCase struct {
+	linkRepo     repository.LinkRepository
+	revisionRepo repository.LinkRevisionRepository
+	shorten      *ShortenUseCase
+	cache        Cache
+}
+
+// NewLinkEditUseCase создаёт новый use case. Проверки новых параметров выполняет
+// ShortenUseCase, поэтому изменённая ссылка проходит те же правила, что и новая.
+func NewLinkEditUseCase(
+	linkRepo repository.LinkRepository,
+	revisionRepo repository.LinkRevisionRepository,
+	shorten *ShortenUseCase,
+	cache Cache,
+) *LinkEditUseCase {
+	return &LinkEditUseCase{
+		linkRepo:     linkRepo,
+		revisionRepo: revisionRepo,
+		shorten:      shorten,
+		cache:        cache,
+	}
+}
+
+// UpdateLinkRequest запрос на изменение ссылки
+type UpdateLinkRequest struct {
+	ShortURL string
+	// Patch изменения в формате JSON Merge Patch (RFC 7396) поверх entity.LinkState:
+	// указанные поля заменяются, null удаляет значение
+	Patch json.RawMessage
+	// ExpectedRevision ревизия, которую видел клиент; 0 — не проверять
+	ExpectedRevision int
+}
+
+// RollbackRequest запрос на откат ссылки к прошлой ревизии
+type RollbackRequest struct {
+	ShortURL         string `json:"-"`
+	Revision         int    `json:"revision"`
+	ExpectedRevision int    `json:"-"`
+}
+
+// LinkHistory история изменений ссылки
+type LinkHistory struct {
+	ShortURL string `json:"short_url"`
+	// Revision номер текущей ревизии
+	Revision  int                    `json:"revision"`
+	Revisions []*entity.LinkRevision `json:"revisions"`
+}
+
+// Update применяет изменения к ссылке и записывает новую ревизию
+func (uc *LinkEditUseCase) Update(ctx context.Context, req UpdateLinkRequest) (*entity.LinkRevision, error) {
+	link, err := uc.getLink(ctx, req.ShortURL, req.ExpectedRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := applyMergePatch(link.State(), req.Patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.save(ctx, link, state, &entity.LinkRevision{Action: entity.RevisionUpdate})
+}
+
+// Rollback восстанавливает состояние ссылки из прошлой ревизии. Откат записывается
+// новой ревизией, поэтому история не теряется и откат можно отменить.
+func (uc *LinkEditUseCase) Rollback(ctx context.Context, req RollbackRequest) (*entity.LinkRevision, error) {
+	if req.Revision <= 0 {
+		return nil, fmt.Errorf("%w: revision must be positive", ErrInvalidLinkUpdate)
+	}
+
+	link, err := uc.getLink(ctx, req.ShortURL, req.ExpectedRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := uc.revisionRepo.GetByRevision(ctx, link.ID, req.Revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	if target == nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	return uc.save(ctx, link, target.State, &entity.LinkRevision{
+		Action:       entity.RevisionRollback,
+		RestoredFrom: target.Revision,
+	})
+}
+
+// History возвращает ревизии ссылки, начиная с последней
+func (uc *LinkEditUseCase) History(ctx context.Context, shortURL string) (*LinkHistory, error) {
+	link, err := uc.getLink(ctx, shortURL, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := uc.revisionRepo.ListByLinkID(ctx, link.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	if revisions == nil {
+		revisions = []*entity.LinkRevision{}
+	}
+
+	return &LinkHistory{
+		ShortURL:  link.ShortURL,
+		Revision:  link.Revision,
+		Revisions: revisions,
+	}, nil
+}
+
+// getLink получает ссылку из БД, минуя кэш, и сверяет ожидаемую ревизию
+func (uc *LinkEditUseCase) getLink(ctx context.Context, shortURL string, expectedRevision int) (*entity.Link, error) {
+	link, err := uc.linkRepo.GetByShortURL(ctx, shortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+	if expectedRevision != 0 && expectedRevision != link.Revision {
+		return nil, ErrRevisionConflict
+	}
+	return link, nil
+}
+
+// save проверяет новое состояние, сохраняет его с ревизией и сбрасывает кэш ссылки
+func (uc *LinkEditUseCase) save(ctx context.Context, link *entity.Link, state entity.LinkState, revision *entity.LinkRevision) (*entity.LinkRevision, error) {
+	if err := uc.shorten.checkState(ctx, &state); err != nil {
+		return nil, err
+	}
+
+	link.ApplyState(state)
+	revision.CreatedAt = time.Now()
+
+	updated, err := uc.linkRepo.Update(ctx, link, revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update link: %w", err)
+	}
+	if !updated {
+		return nil, ErrRevisionConflict
+	}
+
+	// Редирект не должен использовать прежнее состояние из кэша
+	if uc.cache != nil {
+		if err := uc.cache.Delete(ctx, linkCacheKey(link.ShortURL)); err != nil {
+			// Запись истечёт по TTL, изменение уже сохранено
+			_ = err
+		}
+	}
+
+	return revision, nil
+}
+
+// checkState проверяет изменяемые параметры ссылки так же, как при создании,
+// и вычисляет канонический URL
+func (uc *ShortenUseCase) checkState(ctx context.Context, state *entity.LinkState) error {
+	if state.OriginalURL == "" {
+		return ErrURLRequired
+	}
+	if err := ValidateURL(state.OriginalURL); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	canonicalURL, err := uc.checkTarget(ctx, state.OriginalURL)
+	if err != nil {
+		return err
+	}
+	state.CanonicalURL = canonicalURL
+
+	if err := validateInterstitial(state.InterstitialSeconds); err != nil {
+		return err
+	}
+	if err := uc.checkTargetingRules(ctx, state.TargetingRules); err != nil {
+		return err
+	}
+	if err := uc.checkGeoRules(ctx, state.GeoRules); err != nil {
+		return err
+	}
+	if err := uc.checkRoutingRules(ctx, state.Rules); err != nil {
+		return err
+	}
+	if err := uc.checkVariants(ctx, state.Variants); err != nil {
+		return err
+	}
+	if err := uc.checkActiveWindow(ctx, CreateLinkRequest{
+		ActiveFrom:   state.ActiveFrom,
+		ActiveUntil:  state.ActiveUntil,
+		PrelaunchURL: state.PrelaunchURL,
+	}); err != nil {
+		return err
+	}
+
+	openGraph, err := normalizeOpenGraph(state.OpenGraph)
+	if err != nil {
+		return err
+	}
+	state.OpenGraph = openGraph
+
+	deepLink, err := uc.checkDeepLink(ctx, state.DeepLink)
+	if err != nil {
+		return err
+	}
+	state.DeepLink = deepLink
+
+	// Границы окна хранятся в UTC
+	if state.ActiveFrom != nil {
+		activeFrom := state.ActiveFrom.UTC()
+		state.ActiveFrom = &activeFrom
+	}
+	if state.ActiveUntil != nil {
+		activeUntil := state.ActiveUntil.UTC()
+		state.ActiveUntil = &activeUntil
+	}
+
+	return nil
+}
+
+// applyMergePatch применяет JSON Merge Patch к состоянию ссылки. Значения из патча
+// заменяют поля состояния целиком, в том числе объекты open_graph и deep_link.
+func applyMergePatch(state entity.LinkState, patch json.RawMessage) (entity.LinkState, error) {
+	var changes map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &changes); err != nil || changes == nil {
+		return state, fmt.Errorf("%w: body must be a JSON object", ErrInvalidLinkUpdate)
+	}
+	if len(changes) == 0 {
+		return state, fmt.Errorf("%w: no changes", ErrInvalidLinkUpdate)
+	}
+	if _, ok := changes["canonical_url"]; ok {
+		return state, fmt.Errorf("%w: canonical_url is computed from original_url", ErrInvalidLinkUpdate)
+	}
+
+	current, err := json.Marshal(state)
+	if err != nil {
+		return state, fmt.Errorf("failed to encode link state: %w", err)
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(current, &document); err != nil {
+		return state, fmt.Errorf("failed to decode link state: %w", err)
+	}
+
+	for field, value := range changes {
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			delete(document, field)
+			continue
+		}
+		document[field] = value
+	}
+
+	merged, err := json.Marshal(document)
+	if err != nil {
+		return state, fmt.Errorf("failed to encode link state: %w", err)
+	}
+
+	// Неизвестные поля — ошибка, чтобы опечатка не терялась молча
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	var result entity.LinkState
+	if err := decoder.Decode(&result); err != nil {
+		return state, fmt.Errorf("%w: %v", ErrInvalidLinkUpdate, err)
+	}
+
+	return result, nil
+}
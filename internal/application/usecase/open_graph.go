@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// OpenGraphFetcher загружает метаданные Open Graph страницы по адресу.
+// Если на странице нет метаданных, возвращается пустая структура.
+type OpenGraphFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*entity.OpenGraph, error)
+}
+
+// OpenGraphUseCase загружает метаданные Open Graph адреса назначения в ссылку
+type OpenGraphUseCase struct {
+	linkRepo repository.LinkRepository
+	fetcher  OpenGraphFetcher
+	cache    Cache
+}
+
+// NewOpenGraphUseCase создаёт новый use case. Если fetcher равен nil, загрузка отключена,
+// а превью строится только из полей, заданных вручную.
+func NewOpenGraphUseCase(linkRepo repository.LinkRepository, fetcher OpenGraphFetcher, cache Cache) *OpenGraphUseCase {
+	return &OpenGraphUseCase{
+		linkRepo: linkRepo,
+		fetcher:  fetcher,
+		cache:    cache,
+	}
+}
+
+// OpenGraphResponse метаданные превью ссылки
+type OpenGraphResponse struct {
+	ShortURL string `json:"short_url"`
+	// OpenGraph поля, заданные вручную
+	OpenGraph *entity.OpenGraph `json:"open_graph,omitempty"`
+	// FetchedOpenGraph поля, загруженные с адреса назначения
+	FetchedOpenGraph *entity.OpenGraph `json:"fetched_open_graph,omitempty"`
+	// Preview итоговое превью, которое получают боты соцсетей
+	Preview *entity.OpenGraph `json:"preview,omitempty"`
+}
+
+// Get возвращает метаданные превью ссылки
+func (uc *OpenGraphUseCase) Get(ctx context.Context, shortURL string) (*OpenGraphResponse, error) {
+	link, err := uc.getLink(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	return openGraphResponse(link), nil
+}
+
+// Fetch загружает метаданные Open Graph с адреса назначения и сохраняет их в ссылке
+func (uc *OpenGraphUseCase) Fetch(ctx context.Context, shortURL string) (*OpenGraphResponse, error) {
+	if uc.fetcher == nil {
+		return nil, ErrOpenGraphFetchDisabled
+	}
+
+	link, err := uc.getLink(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched, err := uc.fetcher.Fetch(ctx, link.OriginalURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOpenGraphFetchFailed, err)
+	}
+	// Страница может вернуть что угодно, поэтому значения обрезаются, а не отклоняются
+	fetched = sanitizeFetchedOpenGraph(fetched)
+
+	if err := uc.linkRepo.SetFetchedOpenGraph(ctx, link.ID, fetched); err != nil {
+		return nil, fmt.Errorf("failed to save open graph: %w", err)
+	}
+	link.FetchedOpenGraph = fetched
+
+	if uc.cache != nil {
+		if err := uc.cache.Delete(ctx, linkCacheKey(link.ShortURL)); err != nil {
+			// Запись истечёт по TTL
+			_ = err
+		}
+	}
+
+	return openGraphResponse(link), nil
+}
+
+// getLink получает ссылку из БД
+func (uc *OpenGraphUseCase) getLink(ctx context.Context, shortURL string) (*entity.Link, error) {
+	link, err := uc.linkRepo.GetByShortURL(ctx, shortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+	return link, nil
+}
+
+// openGraphResponse собирает ответ из метаданных ссылки
+func openGraphResponse(link *entity.Link) *OpenGraphResponse {
+	return &OpenGraphResponse{
+		ShortURL:         link.ShortURL,
+		OpenGraph:        link.OpenGraph,
+		FetchedOpenGraph: link.FetchedOpenGraph,
+		Preview:          link.SocialPreview(),
+	}
+}
+
+// normalizeOpenGraph проверяет поля превью, заданные вручную. Пустое превью заменяется на nil.
+func normalizeOpenGraph(og *entity.OpenGraph) (*entity.OpenGraph, error) {
+	if og == nil {
+		return nil, nil
+	}
+
+	result := entity.OpenGraph{
+		Title:       strings.TrimSpace(og.Title),
+		Description: strings.TrimSpace(og.Description),
+		Image:       strings.TrimSpace(og.Image),
+	}
+	if utf8.RuneCountInString(result.Title) > MaxOpenGraphTitleLength {
+		return nil, fmt.Errorf("%w: title must be at most %d characters", ErrInvalidOpenGraph, MaxOpenGraphTitleLength)
+	}
+	if utf8.RuneCountInString(result.Description) > MaxOpenGraphDescriptionLength {
+		return nil, fmt.Errorf("%w: description must be at most %d characters",
+			ErrInvalidOpenGraph, MaxOpenGraphDescriptionLength)
+	}
+	if result.Image != "" {
+		if err := ValidateURL(result.Image); err != nil {
+			return nil, fmt.Errorf("%w: image: %v", ErrInvalidOpenGraph, err)
+		}
+	}
+
+	if result.IsZero() {
+		return nil, nil
+	}
+	return &result, nil
+}
+
+// sanitizeFetchedOpenGraph обрезает загруженные поля до допустимой длины и отбрасывает
+// изображение с неподдерживаемым адресом. Пустое превью заменяется на nil.
+func sanitizeFetchedOpenGraph(og *entity.OpenGraph) *entity.OpenGraph {
+	if og == nil {
+		return nil
+	}
+
+	result := entity.OpenGraph{
+		Title:       truncateRunes(strings.TrimSpace(og.Title), MaxOpenGraphTitleLength),
+		Description: truncateRunes(strings.TrimSpace(og.Description), MaxOpenGraphDescriptionLength),
+		Image:       strings.TrimSpace(og.Image),
+	}
+	if result.Image != "" && ValidateURL(result.Image) != nil {
+		result.Image = ""
+	}
+
+	if result.IsZero() {
+		return nil
+	}
+	return &result
+}
+
+// truncateRunes обрезает строку до limit символов
+func truncateRunes(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	runes := []rune(value)
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
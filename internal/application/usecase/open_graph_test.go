@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+func TestSanitizeFetchedOpenGraph(t *testing.T) {
+	longTitle := strings.Repeat("т", MaxOpenGraphTitleLength+50)
+	longDescription := strings.Repeat("d", MaxOpenGraphDescriptionLength+1)
+
+	og := sanitizeFetchedOpenGraph(&entity.OpenGraph{
+		Title:       "  " + longTitle + "  ",
+		Description: longDescription,
+		Image:       "https://example.com/cover.png",
+	})
+
+	if n := utf8.RuneCountInString(og.Title); n != MaxOpenGraphTitleLength {
+		t.Fatalf("title length = %d, want %d", n, MaxOpenGraphTitleLength)
+	}
+	if !strings.HasSuffix(og.Title, "…") || !strings.HasPrefix(og.Title, "т") {
+		t.Fatalf("title = %q, want truncated with ellipsis", og.Title)
+	}
+	if n := utf8.RuneCountInString(og.Description); n != MaxOpenGraphDescriptionLength {
+		t.Fatalf("description length = %d, want %d", n, MaxOpenGraphDescriptionLength)
+	}
+	if og.Image != "https://example.com/cover.png" {
+		t.Fatalf("image = %q", og.Image)
+	}
+}
+
+func TestSanitizeFetchedOpenGraphDropsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		og   *entity.OpenGraph
+		want *entity.OpenGraph
+	}{
+		{"nil", nil, nil},
+		{"empty", &entity.OpenGraph{Title: "  "}, nil},
+		{"short values kept", &entity.OpenGraph{Title: " Title ", Description: "Text"},
+			&entity.OpenGraph{Title: "Title", Description: "Text"}},
+		{"javascript image", &entity.OpenGraph{Title: "Title", Image: "javascript:alert(1)"},
+			&entity.OpenGraph{Title: "Title"}},
+		{"only invalid image", &entity.OpenGraph{Image: "data:image/png;base64,AAAA"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitizeFetchedOpenGraph(tt.og)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("sanitizeFetchedOpenGraph = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
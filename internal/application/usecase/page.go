@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// PageUseCase управляет страницами со списком ссылок и учитывает их просмотры
+type PageUseCase struct {
+	pageRepo  repository.PageRepository
+	linkRepo  repository.LinkRepository
+	clickRepo repository.ClickRepository
+	cache     Cache
+}
+
+// NewPageUseCase создаёт новый use case
+func NewPageUseCase(
+	pageRepo repository.PageRepository,
+	linkRepo repository.LinkRepository,
+	clickRepo repository.ClickRepository,
+	cache Cache,
+) *PageUseCase {
+	return &PageUseCase{
+		pageRepo:  pageRepo,
+		linkRepo:  linkRepo,
+		clickRepo: clickRepo,
+		cache:     cache,
+	}
+}
+
+// PageRequest запрос на создание или замену страницы
+type PageRequest struct {
+	Slug        string            `json:"slug"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Items       []entity.PageItem `json:"items"`
+}
+
+// PageViewRequest запрос на просмотр страницы
+type PageViewRequest struct {
+	Slug      string
+	UserAgent string
+	IPAddress string
+}
+
+// Create создаёт страницу
+func (uc *PageUseCase) Create(ctx context.Context, req PageRequest) (*entity.Page, error) {
+	page, err := uc.newPage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	page.CreatedAt = time.Now()
+	page.UpdatedAt = page.CreatedAt
+
+	if err := uc.pageRepo.Create(ctx, page); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrPageExists
+		}
+		return nil, fmt.Errorf("failed to create page: %w", err)
+	}
+
+	return page, nil
+}
+
+// List возвращает все страницы
+func (uc *PageUseCase) List(ctx context.Context) ([]*entity.Page, error) {
+	pages, err := uc.pageRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pages: %w", err)
+	}
+	if pages == nil {
+		pages = []*entity.Page{}
+	}
+	return pages, nil
+}
+
+// Get возвращает страницу из БД
+func (uc *PageUseCase) Get(ctx context.Context, slug string) (*entity.Page, error) {
+	page, err := uc.pageRepo.GetBySlug(ctx, strings.ToLower(slug))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page: %w", err)
+	}
+	if page == nil {
+		return nil, ErrPageNotFound
+	}
+	return page, nil
+}
+
+// Update заменяет заголовок, описание и пункты страницы
+func (uc *PageUseCase) Update(ctx context.Context, req PageRequest) (*entity.Page, error) {
+	page, err := uc.newPage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	page.UpdatedAt = time.Now()
+
+	updated, err := uc.pageRepo.Update(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update page: %w", err)
+	}
+	if !updated {
+		return nil, ErrPageNotFound
+	}
+	uc.invalidate(ctx, page.Slug)
+
+	return page, nil
+}
+
+// Delete удаляет страницу
+func (uc *PageUseCase) Delete(ctx context.Context, slug string) error {
+	slug = strings.ToLower(slug)
+	deleted, err := uc.pageRepo.Delete(ctx, slug)
+	if err != nil {
+		return fmt.Errorf("failed to delete page: %w", err)
+	}
+	if !deleted {
+		return ErrPageNotFound
+	}
+	uc.invalidate(ctx, slug)
+	return nil
+}
+
+// Analytics возвращает просмотры страницы и переходы по её пунктам
+func (uc *PageUseCase) Analytics(ctx context.Context, slug string) (*entity.PageAnalytics, error) {
+	page, err := uc.Get(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics, err := uc.pageRepo.GetAnalytics(ctx, page.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page analytics: %w", err)
+	}
+	return analytics, nil
+}
+
+// View возвращает страницу для показа и регистрирует просмотр
+func (uc *PageUseCase) View(ctx context.Context, req PageViewRequest) (*entity.Page, error) {
+	page, err := uc.getCached(ctx, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	// Просмотр записывается переходом без ссылки; ошибка не мешает показу страницы
+	if err := uc.clickRepo.Create(ctx, &entity.Click{
+		PageID:    page.ID,
+		UserAgent: req.UserAgent,
+		IPAddress: req.IPAddress,
+		ClickedAt: time.Now(),
+	}); err != nil {
+		_ = err
+	}
+
+	return page, nil
+}
+
+// Item возвращает страницу и её пункт по номеру, начиная с 1. Переход по пункту
+// выполняет RedirectUseCase, чтобы учитывались правила и доступ к ссылке.
+func (uc *PageUseCase) Item(ctx context.Context, slug string, position int) (*entity.Page, *entity.PageItem, error) {
+	page, err := uc.getCached(ctx, slug)
+	if err != nil {
+		return nil, nil, err
+	}
+	if position < 1 || position > len(page.Items) {
+		return nil, nil, ErrPageNotFound
+	}
+	return page, &page.Items[position-1], nil
+}
+
+// getCached получает страницу из кэша или БД
+func (uc *PageUseCase) getCached(ctx context.Context, slug string) (*entity.Page, error) {
+	slug = strings.ToLower(slug)
+	cacheKey := fmt.Sprintf("page:%s", slug)
+
+	if uc.cache != nil {
+		var page entity.Page
+		if err := uc.cache.Get(ctx, cacheKey, &page); err == nil {
+			return &page, nil
+		}
+	}
+
+	page, err := uc.Get(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.cache != nil {
+		if err := uc.cache.Set(ctx, cacheKey, page); err != nil {
+			// Ошибка кэширования не критична
+			_ = err
+		}
+	}
+
+	return page, nil
+}
+
+// invalidate удаляет страницу из кэша после изменения
+func (uc *PageUseCase) invalidate(ctx context.Context, slug string) {
+	if uc.cache == nil {
+		return
+	}
+	if err := uc.cache.Delete(ctx, fmt.Sprintf("page:%s", slug)); err != nil {
+		// Запись истечёт по TTL
+		_ = err
+	}
+}
+
+// newPage проверяет запрос и создаёт по нему страницу
+func (uc *PageUseCase) newPage(ctx context.Context, req PageRequest) (*entity.Page, error) {
+	slug, err := normalizeCatalogName(req.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPage, err)
+	}
+
+	page := &entity.Page{
+		Slug:        slug,
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		Items:       make([]entity.PageItem, 0, len(req.Items)),
+	}
+	if page.Title == "" || utf8.RuneCountInString(page.Title) > MaxPageTitleLength {
+		return nil, fmt.Errorf("%w: title must be 1 to %d characters", ErrInvalidPage, MaxPageTitleLength)
+	}
+	if utf8.RuneCountInString(page.Description) > MaxPageDescriptionLength {
+		return nil, fmt.Errorf("%w: description must be at most %d characters", ErrInvalidPage, MaxPageDescriptionLength)
+	}
+	if len(req.Items) > MaxPageItems {
+		return nil, fmt.Errorf("%w: at most %d items are allowed", ErrInvalidPage, MaxPageItems)
+	}
+
+	for i, item := range req.Items {
+		item.ShortURL = strings.TrimSpace(item.ShortURL)
+		item.Title = strings.TrimSpace(item.Title)
+		item.Icon = strings.TrimSpace(item.Icon)
+
+		if item.Title == "" || utf8.RuneCountInString(item.Title) > MaxPageTitleLength {
+			return nil, fmt.Errorf("%w: item %d: title must be 1 to %d characters", ErrInvalidPage, i, MaxPageTitleLength)
+		}
+		if err := validatePageIcon(item.Icon); err != nil {
+			return nil, fmt.Errorf("%w: item %d: %v", ErrInvalidPage, i, err)
+		}
+
+		exists, err := uc.linkRepo.Exists(ctx, item.ShortURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check page item: %w", err)
+		}
+		if item.ShortURL == "" || !exists {
+			return nil, fmt.Errorf("%w: item %d: link %q not found", ErrInvalidPage, i, item.ShortURL)
+		}
+
+		page.Items = append(page.Items, item)
+	}
+
+	return page, nil
+}
+
+// validatePageIcon проверяет иконку пункта: короткий текст (эмодзи) или адрес изображения
+func validatePageIcon(icon string) error {
+	if icon == "" {
+		return nil
+	}
+	if strings.HasPrefix(icon, "http://") || strings.HasPrefix(icon, "https://") {
+		if err := ValidateURL(icon); err != nil {
+			return fmt.Errorf("icon: %v", err)
+		}
+		return nil
+	}
+	if utf8.RuneCountInString(icon) > MaxPageIconLength {
+		return fmt.Errorf("icon must be an http(s) URL or at most %d characters", MaxPageIconLength)
+	}
+	return nil
+}
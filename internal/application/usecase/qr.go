@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/domain/repository"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// Форматы изображений QR-кода
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+// QROptions параметры изображения QR-кода
+type QROptions struct {
+	Format string
+	// Size ширина и высота изображения в пикселях
+	Size int
+	// ECC уровень коррекции ошибок: L, M, Q или H
+	ECC string
+	// Margin ширина свободной зоны вокруг кода в модулях
+	Margin int
+	// Foreground и Background цвета в формате #rrggbb
+	Foreground string
+	Background string
+	// Logo накладывает логотип в центр кода
+	Logo bool
+}
+
+// QREncoder рисует QR-код для строки в заданном формате
+type QREncoder interface {
+	Encode(content string, opts QROptions) ([]byte, error)
+	// HasLogo сообщает, настроен ли логотип для наложения
+	HasLogo() bool
+}
+
+// QRRequest запрос на получение QR-кода ссылки
+type QRRequest struct {
+	ShortURL string
+	QROptions
+}
+
+// QRImage готовое изображение QR-кода
+type QRImage struct {
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+	// ETag хэш параметров изображения, используется для HTTP-кэширования
+	ETag string `json:"etag"`
+}
+
+// QRUseCase генерирует QR-коды для коротких ссылок
+type QRUseCase struct {
+	linkRepo         repository.LinkRepository
+	shortenerService *service.ShortenerService
+	encoder          QREncoder
+	cache            Cache
+}
+
+// NewQRUseCase создаёт новый use case
+func NewQRUseCase(
+	linkRepo repository.LinkRepository,
+	shortenerService *service.ShortenerService,
+	encoder QREncoder,
+	cache Cache,
+) *QRUseCase {
+	return &QRUseCase{
+		linkRepo:         linkRepo,
+		shortenerService: shortenerService,
+		encoder:          encoder,
+		cache:            cache,
+	}
+}
+
+// Execute возвращает QR-код для BuildShortURL ссылки. Изображения кэшируются по хэшу параметров.
+// Ссылка проверяется до обращения к кэшу, чтобы после удаления ссылки код не отдавался из кэша.
+// Для ссылок, требующих подписи, QR-код не выдаётся: неподписанный адрес не откроется,
+// а подписанный нельзя раздавать через публичный эндпоинт.
+func (uc *QRUseCase) Execute(ctx context.Context, req QRRequest) (*QRImage, error) {
+	opts, err := uc.normalizeOptions(req.QROptions)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := uc.linkRepo.GetByShortURL(ctx, req.ShortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+	if link.RequireSignature {
+		return nil, ErrSignatureRequired
+	}
+
+	content := uc.shortenerService.BuildShortURL(link.ShortURL)
+	hash := qrHash(content, opts)
+	cacheKey := "qr:" + hash
+
+	if uc.cache != nil {
+		cached := &QRImage{}
+		if err := uc.cache.Get(ctx, cacheKey, cached); err == nil {
+			return cached, nil
+		}
+	}
+
+	data, err := uc.encoder.Encode(content, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	image := &QRImage{
+		ContentType: "image/png",
+		Data:        data,
+		ETag:        `"` + hash + `"`,
+	}
+	if opts.Format == QRFormatSVG {
+		image.ContentType = "image/svg+xml"
+	}
+
+	if uc.cache != nil {
+		if err := uc.cache.SetWithTTL(ctx, cacheKey, image, QRCacheTTL); err != nil {
+			// Ошибка кэширования не критична, продолжаем работу
+			_ = err
+		}
+	}
+
+	return image, nil
+}
+
+// normalizeOptions подставляет значения по умолчанию и проверяет параметры
+func (uc *QRUseCase) normalizeOptions(opts QROptions) (QROptions, error) {
+	opts.Format = strings.ToLower(opts.Format)
+	if opts.Format == "" {
+		opts.Format = QRFormatPNG
+	}
+	if opts.Format != QRFormatPNG && opts.Format != QRFormatSVG {
+		return opts, fmt.Errorf("%w: format must be png or svg", ErrInvalidQROptions)
+	}
+
+	if opts.Size == 0 {
+		opts.Size = DefaultQRSize
+	}
+	if opts.Size < MinQRSize || opts.Size > MaxQRSize {
+		return opts, fmt.Errorf("%w: size must be between %d and %d", ErrInvalidQROptions, MinQRSize, MaxQRSize)
+	}
+
+	if opts.Margin < 0 || opts.Margin > MaxQRMargin {
+		return opts, fmt.Errorf("%w: margin must be between 0 and %d", ErrInvalidQROptions, MaxQRMargin)
+	}
+
+	opts.ECC = strings.ToUpper(opts.ECC)
+	switch opts.ECC {
+	case "":
+		opts.ECC = "M"
+	case "L", "M", "Q", "H":
+	default:
+		return opts, fmt.Errorf("%w: ecc must be one of L, M, Q, H", ErrInvalidQROptions)
+	}
+
+	var err error
+	if opts.Foreground, err = normalizeHexColor(opts.Foreground, "#000000"); err != nil {
+		return opts, fmt.Errorf("%w: fg: %v", ErrInvalidQROptions, err)
+	}
+	if opts.Background, err = normalizeHexColor(opts.Background, "#ffffff"); err != nil {
+		return opts, fmt.Errorf("%w: bg: %v", ErrInvalidQROptions, err)
+	}
+
+	if opts.Logo {
+		if uc.encoder == nil || !uc.encoder.HasLogo() {
+			return opts, fmt.Errorf("%w: logo is not configured", ErrInvalidQROptions)
+		}
+		// Логотип закрывает часть модулей, поэтому нужен высокий уровень коррекции
+		if opts.ECC == "L" || opts.ECC == "M" {
+			opts.ECC = "H"
+		}
+	}
+
+	return opts, nil
+}
+
+// normalizeHexColor приводит цвет вида "fff", "#fff" или "ffffff" к виду "#ffffff"
+func normalizeHexColor(value, fallback string) (string, error) {
+	value = strings.ToLower(strings.TrimPrefix(value, "#"))
+	if value == "" {
+		return fallback, nil
+	}
+	if len(value) == 3 {
+		value = string([]byte{value[0], value[0], value[1], value[1], value[2], value[2]})
+	}
+	if len(value) != 6 {
+		return "", fmt.Errorf("color must be in #rgb or #rrggbb format")
+	}
+	if _, err := hex.DecodeString(value); err != nil {
+		return "", fmt.Errorf("color must be in #rgb or #rrggbb format")
+	}
+	return "#" + value, nil
+}
+
+// qrHash вычисляет хэш содержимого и параметров изображения
+func qrHash(content string, opts QROptions) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%s|%d|%s|%s|%t",
+		content, opts.Format, opts.Size, opts.ECC, opts.Margin, opts.Foreground, opts.Background, opts.Logo)))
+	return hex.EncodeToString(sum[:16])
+}
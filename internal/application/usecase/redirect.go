@@ -2,18 +2,27 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/oziev02/Shortener/internal/domain/entity"
 	"github.com/oziev02/Shortener/internal/domain/repository"
+	"github.com/oziev02/Shortener/internal/domain/service"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // RedirectUseCase обрабатывает редиректы по коротким ссылкам
 type RedirectUseCase struct {
-	linkRepo  repository.LinkRepository
-	clickRepo repository.ClickRepository
-	cache     Cache
+	linkRepo    repository.LinkRepository
+	clickRepo   repository.ClickRepository
+	cache       Cache
+	domainRules *DomainRulesUseCase
+	signer      *service.URLSigner
+	geoResolver GeoResolver
+	evaluator   *service.RuleEvaluator
 }
 
 // NewRedirectUseCase создаёт новый use case
@@ -21,60 +30,301 @@ func NewRedirectUseCase(
 	linkRepo repository.LinkRepository,
 	clickRepo repository.ClickRepository,
 	cache Cache,
+	domainRules *DomainRulesUseCase,
+	signer *service.URLSigner,
+	geoResolver GeoResolver,
 ) *RedirectUseCase {
 	return &RedirectUseCase{
-		linkRepo:  linkRepo,
-		clickRepo: clickRepo,
-		cache:     cache,
+		linkRepo:    linkRepo,
+		clickRepo:   clickRepo,
+		cache:       cache,
+		domainRules: domainRules,
+		signer:      signer,
+		geoResolver: geoResolver,
+		evaluator:   service.NewRuleEvaluator(),
 	}
 }
 
+// RedirectRequest запрос на переход по короткой ссылке
+type RedirectRequest struct {
+	ShortURL  string
+	UserAgent string
+	IPAddress string
+	// Headers заголовки запроса для правил маршрутизации
+	Headers http.Header
+	// Expires и Signature параметры exp и sig подписанного URL
+	Expires   string
+	Signature string
+	// VisitorID стабильный идентификатор посетителя для закрепления варианта A/B-теста
+	VisitorID string
+	// UnlockedVersion версия ссылки, для которой клиент уже подтвердил пароль; пусто — не подтверждал
+	UnlockedVersion string
+	// PageID страница ссылок, с которой выполнен переход
+	PageID int64
+}
+
+// RedirectResult результат перехода по короткой ссылке
+type RedirectResult struct {
+	Link *entity.Link
+	// URL адрес, на который нужно перенаправить клиента
+	URL string
+	// MatchedRule идентификатор сработавшего правила; пусто — основной адрес ссылки
+	MatchedRule string
+	// Variant выбранный вариант A/B-теста
+	Variant string
+	// Pending означает, что окно активности ещё не открылось: URL — адрес до запуска,
+	// а если он пуст, клиенту показывается страница «скоро»
+	Pending bool
+	// DeepLink адреса страницы открытия приложения; nil — обычный редирект
+	DeepLink *DeepLinkTarget
+}
+
 // Execute получает оригинальный URL и регистрирует переход
-func (uc *RedirectUseCase) Execute(ctx context.Context, shortURL string, userAgent string, ipAddress string) (string, error) {
-	var link *entity.Link
-	var err error
+func (uc *RedirectUseCase) Execute(ctx context.Context, req RedirectRequest) (*RedirectResult, error) {
+	link, err := uc.resolve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
-	// Пытаемся получить из кэша
-	if uc.cache != nil {
-		cacheKey := fmt.Sprintf("link:%s", shortURL)
-		cachedLink := &entity.Link{}
-		if err := uc.cache.Get(ctx, cacheKey, cachedLink); err == nil {
-			link = cachedLink
-		}
+	if link.IsPending(time.Now()) {
+		return uc.prelaunch(ctx, link, req)
 	}
 
-	// Если не в кэше, получаем из БД
-	if link == nil {
-		link, err = uc.linkRepo.GetByShortURL(ctx, shortURL)
-		if err != nil {
-			return "", fmt.Errorf("failed to get link: %w", err)
+	result := &RedirectResult{
+		Link: link,
+		URL:  link.OriginalURL,
+	}
+
+	var location GeoLocation
+	if uc.geoResolver != nil {
+		location, _ = uc.geoResolver.Lookup(req.IPAddress)
+	}
+
+	// Правила по устройству проверяются раньше гео-правил и правил маршрутизации,
+	// варианты A/B-теста — последними
+	targetURL, rule, ok := matchTargeting(link.TargetingRules, req.UserAgent)
+	if !ok {
+		targetURL, rule, ok = matchGeo(link.GeoRules, location)
+	}
+	if !ok {
+		rc := service.RuleContext{Headers: req.Headers, Now: time.Now()}
+		targetURL, rule, ok = matchRoutingRules(uc.evaluator, link.Rules, rc)
+	}
+	if ok {
+		// Адрес правила мог попасть под блокировку так же, как основной
+		if err := uc.checkDomainRules(ctx, targetURL); err != nil {
+			return nil, err
 		}
-		if link == nil {
-			return "", ErrLinkNotFound
+		result.URL = targetURL
+		result.MatchedRule = rule
+	} else if variant := pickVariant(link.Variants, req.VisitorID, link.ShortURL); variant != nil {
+		if err := uc.checkDomainRules(ctx, variant.URL); err != nil {
+			return nil, err
 		}
+		result.URL = variant.URL
+		result.Variant = variant.Name
+	}
 
-		// Сохраняем в кэш
-		if uc.cache != nil {
-			cacheKey := fmt.Sprintf("link:%s", shortURL)
-			if err := uc.cache.Set(ctx, cacheKey, link); err != nil {
-				// Ошибка кэширования не критична, продолжаем работу
-				_ = err
-			}
+	if link.DeepLink != nil {
+		target, err := uc.deepLinkTarget(ctx, link.DeepLink, req.UserAgent, result.URL)
+		if err != nil {
+			return nil, err
 		}
+		result.DeepLink = target
 	}
 
 	// Регистрируем переход
-	click := &entity.Click{
-		LinkID:    link.ID,
-		UserAgent: userAgent,
-		IPAddress: ipAddress,
-		ClickedAt: time.Now(),
+	uc.recordClick(ctx, &entity.Click{
+		LinkID:      link.ID,
+		UserAgent:   req.UserAgent,
+		IPAddress:   req.IPAddress,
+		MatchedRule: result.MatchedRule,
+		Country:     location.Country,
+		Variant:     result.Variant,
+		Revision:    link.Revision,
+		UTM:         clickUTM(result.URL),
+		PageID:      req.PageID,
+		ClickedAt:   time.Now(),
+	})
+
+	return result, nil
+}
+
+// prelaunch обрабатывает переход до открытия окна активности. Переход на адрес до запуска
+// регистрируется с правилом "prelaunch", показ страницы «скоро» переходом не считается.
+func (uc *RedirectUseCase) prelaunch(ctx context.Context, link *entity.Link, req RedirectRequest) (*RedirectResult, error) {
+	result := &RedirectResult{
+		Link:    link,
+		URL:     link.PrelaunchURL,
+		Pending: true,
+	}
+	if result.URL == "" {
+		return result, nil
 	}
+
+	if err := uc.checkDomainRules(ctx, result.URL); err != nil {
+		return nil, err
+	}
+	result.MatchedRule = "prelaunch"
+
+	uc.recordClick(ctx, &entity.Click{
+		LinkID:      link.ID,
+		UserAgent:   req.UserAgent,
+		IPAddress:   req.IPAddress,
+		MatchedRule: result.MatchedRule,
+		Revision:    link.Revision,
+		UTM:         clickUTM(result.URL),
+		PageID:      req.PageID,
+		ClickedAt:   time.Now(),
+	})
+
+	return result, nil
+}
+
+// recordClick сохраняет переход; ошибка не прерывает редирект
+func (uc *RedirectUseCase) recordClick(ctx context.Context, click *entity.Click) {
 	if err := uc.clickRepo.Create(ctx, click); err != nil {
 		// Логируем ошибку, но не прерываем редирект
 		// В реальном приложении здесь должен быть логгер
 		_ = err
 	}
+}
+
+// Preview возвращает ссылку для страницы предпросмотра. Проверки доступа те же,
+// что и при переходе, но переход не регистрируется.
+func (uc *RedirectUseCase) Preview(ctx context.Context, req RedirectRequest) (*entity.Link, error) {
+	return uc.resolve(ctx, req)
+}
+
+// resolve получает ссылку и проверяет, что по ней можно перейти
+func (uc *RedirectUseCase) resolve(ctx context.Context, req RedirectRequest) (*entity.Link, error) {
+	link, err := uc.getLink(ctx, req.ShortURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if link.IsExpired(time.Now()) {
+		return nil, ErrLinkExpired
+	}
+
+	// Цель могла попасть под блокировку уже после создания ссылки
+	if err := uc.checkDomainRules(ctx, domainRuleTarget(link)); err != nil {
+		return nil, err
+	}
+
+	if link.RequireSignature {
+		if err := uc.verifySignature(req); err != nil {
+			return nil, err
+		}
+	}
+
+	// Защищённая ссылка проверяется всегда, в том числе при попадании в кэш.
+	// Подтверждение, выданное до изменения ссылки, больше не действует.
+	if link.Protected && req.UnlockedVersion != UnlockVersion(link) {
+		return nil, ErrPasswordRequired
+	}
+
+	return link, nil
+}
+
+// Unlock проверяет пароль защищённой ссылки и возвращает версию ссылки, для которой
+// пароль подтверждён. Хэш пароля всегда читается из БД, так как в кэш он не попадает.
+func (uc *RedirectUseCase) Unlock(ctx context.Context, shortURL string, password string) (string, error) {
+	link, err := uc.linkRepo.GetByShortURL(ctx, shortURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil {
+		return "", ErrLinkNotFound
+	}
+	if !link.Protected {
+		return UnlockVersion(link), nil
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)); err != nil {
+		return "", ErrWrongPassword
+	}
+	return UnlockVersion(link), nil
+}
+
+// UnlockVersion возвращает версию ссылки для подтверждения пароля. Она меняется при каждом
+// изменении ссылки и при её пересоздании с тем же кодом, поэтому смена пароля отзывает
+// выданные подтверждения.
+func UnlockVersion(link *entity.Link) string {
+	return strconv.FormatInt(link.ID, 10) + "." + strconv.Itoa(link.Revision)
+}
+
+// checkDomainRules проверяет адрес назначения по правилам доменов, если проверка при редиректе включена
+func (uc *RedirectUseCase) checkDomainRules(ctx context.Context, targetURL string) error {
+	if uc.domainRules == nil {
+		return nil
+	}
+	if err := uc.domainRules.Check(ctx, targetURL); err != nil {
+		if errors.Is(err, ErrDomainBlocked) {
+			return fmt.Errorf("%w: %v", ErrLinkBlocked, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// domainRuleTarget возвращает адрес для проверки правил доменов. У ссылок из кэша,
+// записанных до появления канонического URL, и у строк, пропущенных при заполнении,
+// CanonicalURL пуст, поэтому проверяется исходный URL.
+func domainRuleTarget(link *entity.Link) string {
+	if link.CanonicalURL != "" {
+		return link.CanonicalURL
+	}
+	return link.OriginalURL
+}
+
+// verifySignature проверяет подпись и срок действия URL
+func (uc *RedirectUseCase) verifySignature(req RedirectRequest) error {
+	if req.Expires == "" && req.Signature == "" {
+		return ErrSignatureRequired
+	}
+	// Без ключей подпись проверить нельзя, поэтому такие ссылки недоступны
+	if uc.signer == nil {
+		return ErrInvalidSignature
+	}
+
+	err := uc.signer.Verify(req.ShortURL, req.Expires, req.Signature, time.Now())
+	switch {
+	case errors.Is(err, service.ErrSignatureExpired):
+		return ErrSignatureExpired
+	case err != nil:
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// getLink получает ссылку из кэша или БД
+func (uc *RedirectUseCase) getLink(ctx context.Context, shortURL string) (*entity.Link, error) {
+	// Пытаемся получить из кэша
+	if uc.cache != nil {
+		cacheKey := linkCacheKey(shortURL)
+		cachedLink := &entity.Link{}
+		if err := uc.cache.Get(ctx, cacheKey, cachedLink); err == nil {
+			return cachedLink, nil
+		}
+	}
+
+	// Если не в кэше, получаем из БД
+	link, err := uc.linkRepo.GetByShortURL(ctx, shortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+
+	// Сохраняем в кэш
+	if uc.cache != nil {
+		if err := cacheLink(ctx, uc.cache, link); err != nil {
+			// Ошибка кэширования не критична, продолжаем работу
+			_ = err
+		}
+	}
 
-	return link.OriginalURL, nil
+	return link, nil
 }
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+func TestPasswordUnlockTiedToLinkVersion(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("first-pass"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := &entity.Link{ID: 7, ShortURL: "locked", OriginalURL: "https://example.com/",
+		Protected: true, PasswordHash: string(hash), Revision: 1}
+	repo := &fakeLinkRepo{links: map[string]*entity.Link{"locked": link}}
+	uc := NewRedirectUseCase(repo, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	if _, err := uc.Unlock(ctx, "locked", "wrong-pass"); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("Unlock = %v, want ErrWrongPassword", err)
+	}
+	version, err := uc.Unlock(ctx, "locked", "first-pass")
+	if err != nil {
+		t.Fatalf("Unlock = %v", err)
+	}
+
+	if _, err := uc.Preview(ctx, RedirectRequest{ShortURL: "locked"}); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("Preview without unlock = %v, want ErrPasswordRequired", err)
+	}
+	if _, err := uc.Preview(ctx, RedirectRequest{ShortURL: "locked", UnlockedVersion: version}); err != nil {
+		t.Fatalf("Preview after unlock = %v", err)
+	}
+
+	// Изменение ссылки (например, смена пароля) создаёт новую ревизию
+	link.Revision++
+	if _, err := uc.Preview(ctx, RedirectRequest{ShortURL: "locked", UnlockedVersion: version}); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("Preview after change = %v, want ErrPasswordRequired", err)
+	}
+
+	// Ссылка, пересозданная с тем же кодом, не принимает старое подтверждение
+	link.ID, link.Revision = 8, 1
+	if _, err := uc.Preview(ctx, RedirectRequest{ShortURL: "locked", UnlockedVersion: version}); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("Preview after recreate = %v, want ErrPasswordRequired", err)
+	}
+}
+
+// fakeDomainRuleRepo хранит правила доменов в памяти
+type fakeDomainRuleRepo struct {
+	repository.DomainRuleRepository
+	rules []*entity.DomainRule
+}
+
+func (r *fakeDomainRuleRepo) List(ctx context.Context) ([]*entity.DomainRule, error) {
+	return r.rules, nil
+}
+
+func TestDomainRulesFallBackToOriginalURL(t *testing.T) {
+	ruleRepo := &fakeDomainRuleRepo{rules: []*entity.DomainRule{
+		{ID: 1, Action: entity.DomainRuleBlock, Kind: entity.DomainRuleExact, Pattern: "blocked.example"},
+	}}
+	repo := &fakeLinkRepo{links: map[string]*entity.Link{
+		"blocked": {ID: 1, ShortURL: "blocked", OriginalURL: "https://blocked.example/page"},
+		"allowed": {ID: 2, ShortURL: "allowed", OriginalURL: "https://allowed.example/page"},
+	}}
+	uc := NewRedirectUseCase(repo, nil, nil, NewDomainRulesUseCase(ruleRepo, false, time.Minute), nil, nil)
+	ctx := context.Background()
+
+	if _, err := uc.Preview(ctx, RedirectRequest{ShortURL: "blocked"}); !errors.Is(err, ErrLinkBlocked) {
+		t.Fatalf("Preview blocked = %v, want ErrLinkBlocked", err)
+	}
+	if _, err := uc.Preview(ctx, RedirectRequest{ShortURL: "allowed"}); err != nil {
+		t.Fatalf("Preview allowed = %v", err)
+	}
+}
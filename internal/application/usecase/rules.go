@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// checkRoutingRules проверяет правила маршрутизации: структуру условий и адреса назначения
+func (uc *ShortenUseCase) checkRoutingRules(ctx context.Context, rules []entity.RoutingRule) error {
+	if len(rules) > MaxRoutingRules {
+		return fmt.Errorf("%w: at most %d rules are allowed", ErrInvalidRoutingRule, MaxRoutingRules)
+	}
+
+	for i, rule := range rules {
+		if err := uc.ruleEvaluator.Validate(rule.When); err != nil {
+			return fmt.Errorf("%w: rule %d: %v", ErrInvalidRoutingRule, i, err)
+		}
+		if err := uc.checkRuleURL(ctx, rule.URL); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// matchRoutingRules возвращает URL первого правила, условие которого выполнено, и его идентификатор
+func matchRoutingRules(evaluator *service.RuleEvaluator, rules []entity.RoutingRule, rc service.RuleContext) (string, string, bool) {
+	for i, rule := range rules {
+		if evaluator.Evaluate(rule.When, rc).Matched {
+			return rule.URL, "rule:" + strconv.Itoa(i), true
+		}
+	}
+	return "", "", false
+}
+
+// ExplainRulesRequest запрос на проверку правил маршрутизации. Правила берутся из запроса
+// или из существующей ссылки, если указан ShortURL. Правила ссылок с паролем или
+// обязательной подписью не раскрываются: их адреса назначения скрыты.
+type ExplainRulesRequest struct {
+	ShortURL string               `json:"short_url,omitempty"`
+	Rules    []entity.RoutingRule `json:"rules,omitempty"`
+	// Headers заголовки тестового запроса
+	Headers map[string]string `json:"headers,omitempty"`
+	// Time момент вычисления; по умолчанию текущее время
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// RuleTrace результат вычисления одного правила
+type RuleTrace struct {
+	Index   int                     `json:"index"`
+	Name    string                  `json:"name,omitempty"`
+	URL     string                  `json:"url"`
+	Matched bool                    `json:"matched"`
+	Result  service.ConditionResult `json:"result"`
+}
+
+// ExplainRulesResponse объяснение выбора адреса по правилам
+type ExplainRulesResponse struct {
+	// MatchedRule индекс первого сработавшего правила; nil — ни одно не сработало
+	MatchedRule *int        `json:"matched_rule"`
+	URL         string      `json:"url,omitempty"`
+	EvaluatedAt time.Time   `json:"evaluated_at"`
+	Rules       []RuleTrace `json:"rules"`
+}
+
+// ExplainRules проверяет правила и вычисляет их для тестового запроса, объясняя результат
+// каждого условия. В отличие от редиректа, вычисляются все правила, а не только до первого совпадения.
+func (uc *ShortenUseCase) ExplainRules(ctx context.Context, req ExplainRulesRequest) (*ExplainRulesResponse, error) {
+	rules := req.Rules
+	if req.ShortURL != "" {
+		link, err := uc.linkRepo.GetByShortURL(ctx, req.ShortURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get link: %w", err)
+		}
+		if link == nil {
+			return nil, ErrLinkNotFound
+		}
+		if link.Protected {
+			return nil, ErrPasswordRequired
+		}
+		if link.RequireSignature {
+			return nil, ErrSignatureRequired
+		}
+		rules = link.Rules
+	} else if err := uc.checkRoutingRules(ctx, rules); err != nil {
+		return nil, err
+	}
+
+	headers := make(http.Header, len(req.Headers))
+	for name, value := range req.Headers {
+		headers.Set(name, value)
+	}
+	now := time.Now()
+	if req.Time != nil {
+		now = *req.Time
+	}
+	rc := service.RuleContext{Headers: headers, Now: now}
+
+	resp := &ExplainRulesResponse{
+		EvaluatedAt: now,
+		Rules:       make([]RuleTrace, 0, len(rules)),
+	}
+	for i, rule := range rules {
+		result := uc.ruleEvaluator.Evaluate(rule.When, rc)
+		resp.Rules = append(resp.Rules, RuleTrace{
+			Index:   i,
+			Name:    rule.Name,
+			URL:     rule.URL,
+			Matched: result.Matched,
+			Result:  result,
+		})
+		if result.Matched && resp.MatchedRule == nil {
+			index := i
+			resp.MatchedRule = &index
+			resp.URL = rule.URL
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+func TestExplainRulesHidesProtectedLinks(t *testing.T) {
+	rules := []entity.RoutingRule{{
+		When: entity.Condition{Header: &entity.HeaderCondition{Name: "X-Beta"}},
+		URL:  "https://example.com/hidden",
+	}}
+	repo := &fakeLinkRepo{links: map[string]*entity.Link{
+		"open":   {ShortURL: "open", Rules: rules},
+		"locked": {ShortURL: "locked", Rules: rules, Protected: true, PasswordHash: "hash"},
+		"signed": {ShortURL: "signed", Rules: rules, RequireSignature: true},
+	}}
+	uc := NewShortenUseCase(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	tests := []struct {
+		shortURL string
+		wantErr  error
+	}{
+		{"open", nil},
+		{"locked", ErrPasswordRequired},
+		{"signed", ErrSignatureRequired},
+		{"missing", ErrLinkNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shortURL, func(t *testing.T) {
+			resp, err := uc.ExplainRules(context.Background(), ExplainRulesRequest{
+				ShortURL: tt.shortURL,
+				Headers:  map[string]string{"X-Beta": "1"},
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExplainRules = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if resp != nil {
+					t.Fatalf("response = %+v, want nil", resp)
+				}
+				return
+			}
+			if len(resp.Rules) != 1 || resp.URL != "https://example.com/hidden" {
+				t.Fatalf("response = %+v, want the link rules", resp)
+			}
+		})
+	}
+}
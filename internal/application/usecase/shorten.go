@@ -2,11 +2,17 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+
 	"github.com/oziev02/Shortener/internal/domain/entity"
 	"github.com/oziev02/Shortener/internal/domain/repository"
 	"github.com/oziev02/Shortener/internal/domain/service"
@@ -17,6 +23,14 @@ type ShortenUseCase struct {
 	linkRepo         repository.LinkRepository
 	shortenerService *service.ShortenerService
 	cache            Cache
+	idempotencyStore Cache
+	normalizer       *service.URLNormalizer
+	urlPolicy        *service.URLPolicy
+	domainRules      *DomainRulesUseCase
+	campaignRepo     repository.CampaignRepository
+	utmTemplateRepo  repository.UTMTemplateRepository
+	aliasPolicy      *service.AliasPolicy
+	ruleEvaluator    *service.RuleEvaluator
 }
 
 // NewShortenUseCase создаёт новый use case
@@ -24,11 +38,26 @@ func NewShortenUseCase(
 	linkRepo repository.LinkRepository,
 	shortenerService *service.ShortenerService,
 	cache Cache,
+	idempotencyStore Cache,
+	normalizer *service.URLNormalizer,
+	urlPolicy *service.URLPolicy,
+	domainRules *DomainRulesUseCase,
+	campaignRepo repository.CampaignRepository,
+	utmTemplateRepo repository.UTMTemplateRepository,
+	aliasPolicy *service.AliasPolicy,
 ) *ShortenUseCase {
 	return &ShortenUseCase{
 		linkRepo:         linkRepo,
 		shortenerService: shortenerService,
 		cache:            cache,
+		idempotencyStore: idempotencyStore,
+		normalizer:       normalizer,
+		urlPolicy:        urlPolicy,
+		domainRules:      domainRules,
+		campaignRepo:     campaignRepo,
+		utmTemplateRepo:  utmTemplateRepo,
+		aliasPolicy:      aliasPolicy,
+		ruleEvaluator:    service.NewRuleEvaluator(),
 	}
 }
 
@@ -36,37 +65,144 @@ func NewShortenUseCase(
 type CreateLinkRequest struct {
 	OriginalURL string `json:"original_url"`
 	CustomAlias string `json:"custom_alias,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	// ReuseExisting возвращает существующую ссылку владельца на тот же URL вместо создания новой
+	ReuseExisting bool `json:"reuse_existing,omitempty"`
+	// Password защищает переход по ссылке паролем
+	Password string `json:"password,omitempty"`
+	// RequireSignature разрешает переход только по подписанному URL
+	RequireSignature bool `json:"require_signature,omitempty"`
+	// InterstitialSeconds показывает промежуточную страницу с обратным отсчётом перед редиректом
+	InterstitialSeconds int `json:"interstitial_seconds,omitempty"`
+	// TargetingRules правила выбора адреса по ОС, типу устройства и браузеру
+	TargetingRules []entity.TargetingRule `json:"targeting_rules,omitempty"`
+	// GeoRules правила выбора адреса по стране или континенту
+	GeoRules []entity.GeoRule `json:"geo_rules,omitempty"`
+	// Rules правила маршрутизации с условиями на заголовки, язык и расписание
+	Rules []entity.RoutingRule `json:"rules,omitempty"`
+	// Variants варианты адреса для A/B-теста с весами
+	Variants []entity.Variant `json:"variants,omitempty"`
+	// ActiveFrom и ActiveUntil окно, в котором ссылка работает
+	ActiveFrom  *time.Time `json:"active_from,omitempty"`
+	ActiveUntil *time.Time `json:"active_until,omitempty"`
+	// PrelaunchURL адрес для переходов до начала окна
+	PrelaunchURL string `json:"prelaunch_url,omitempty"`
+	// Tags теги ссылки
+	Tags []string `json:"tags,omitempty"`
+	// Campaign имя существующей кампании
+	Campaign string `json:"campaign,omitempty"`
+	// UTMTemplate имя шаблона, метки которого добавляются в адрес, если их там нет
+	UTMTemplate string `json:"utm_template,omitempty"`
+	// UTM метки, которые добавляются в адрес с заменой значений из шаблона и адреса
+	UTM *entity.UTMParams `json:"utm,omitempty"`
+	// OpenGraph заголовок, описание и изображение превью для соцсетей
+	OpenGraph *entity.OpenGraph `json:"open_graph,omitempty"`
+	// DeepLink адреса для открытия ссылки в мобильном приложении
+	DeepLink *entity.DeepLink `json:"deep_link,omitempty"`
+
+	// passwordHash готовый bcrypt-хэш пароля; задаётся только импортом,
+	// чтобы перенести защиту ссылки без исходного пароля
+	passwordHash string
 }
 
 // CreateLinkResponse ответ с созданной ссылкой
 type CreateLinkResponse struct {
 	ShortURL    string `json:"short_url"`
 	OriginalURL string `json:"original_url"`
+	// Reused означает, что возвращена ранее созданная ссылка
+	Reused bool `json:"reused,omitempty"`
+}
+
+// idempotencyRecord запись о запросе с ключом идемпотентности
+type idempotencyRecord struct {
+	RequestHash string              `json:"request_hash"`
+	Completed   bool                `json:"completed"`
+	Response    *CreateLinkResponse `json:"response,omitempty"`
+}
+
+// ExecuteIdempotent создаёт ссылку с учётом ключа идемпотентности.
+// Повторный запрос с тем же ключом получает сохранённый ответ; replayed = true.
+func (uc *ShortenUseCase) ExecuteIdempotent(ctx context.Context, key string, req CreateLinkRequest) (resp *CreateLinkResponse, replayed bool, err error) {
+	if uc.idempotencyStore == nil {
+		resp, err = uc.Execute(ctx, req)
+		return resp, false, err
+	}
+
+	requestHash, err := hashRequest(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Ключи разных владельцев не пересекаются
+	cacheKey := fmt.Sprintf("idempotency:%s:%s", req.Owner, key)
+	pending := idempotencyRecord{RequestHash: requestHash}
+
+	reserved, err := uc.idempotencyStore.SetIfNotExists(ctx, cacheKey, pending, IdempotencyLockTTL)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if !reserved {
+		var record idempotencyRecord
+		if err := uc.idempotencyStore.Get(ctx, cacheKey, &record); err != nil {
+			// Запись могла истечь между проверками, просим клиента повторить запрос
+			return nil, false, ErrIdempotencyInProgress
+		}
+		if record.RequestHash != requestHash {
+			return nil, false, ErrIdempotencyKeyReused
+		}
+		if !record.Completed {
+			return nil, false, ErrIdempotencyInProgress
+		}
+		return record.Response, true, nil
+	}
+
+	resp, err = uc.Execute(ctx, req)
+	if err != nil {
+		// Освобождаем ключ, чтобы клиент мог повторить запрос после ошибки
+		_ = uc.idempotencyStore.Delete(ctx, cacheKey)
+		return nil, false, err
+	}
+
+	completed := idempotencyRecord{RequestHash: requestHash, Completed: true, Response: resp}
+	if err := uc.idempotencyStore.SetWithTTL(ctx, cacheKey, completed, IdempotencyTTL); err != nil {
+		// Ссылка уже создана, поэтому ошибку сохранения ответа не возвращаем
+		_ = err
+	}
+
+	return resp, false, nil
 }
 
 // Execute создаёт новую короткую ссылку
 func (uc *ShortenUseCase) Execute(ctx context.Context, req CreateLinkRequest) (*CreateLinkResponse, error) {
 	var shortURL string
-	var err error
 
-	// Если указан кастомный алиас, используем его
-	if req.CustomAlias != "" {
-		// Проверяем, не занят ли алиас как custom_alias
-		existing, err := uc.linkRepo.GetByCustomAlias(ctx, req.CustomAlias)
+	validated, err := uc.validateRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	req.OriginalURL = validated.originalURL
+	req.CustomAlias = validated.customAlias
+	canonicalURL := validated.canonicalURL
+
+	// В режиме переиспользования возвращаем уже существующую ссылку владельца
+	if canReuse(req) {
+		existing, err := uc.linkRepo.GetByOwnerAndCanonicalURL(ctx, req.Owner, canonicalURL)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check alias: %w", err)
+			return nil, fmt.Errorf("failed to find existing link: %w", err)
 		}
-		if existing != nil {
-			return nil, ErrAliasExists
+		if existing != nil && isReusable(existing, req) {
+			return &CreateLinkResponse{
+				ShortURL:    uc.shortenerService.BuildShortURL(existing.ShortURL),
+				OriginalURL: existing.OriginalURL,
+				Reused:      true,
+			}, nil
 		}
+	}
 
-		// Проверяем, не занят ли алиас как short_url (так как shortURL = customAlias)
-		exists, err := uc.linkRepo.Exists(ctx, req.CustomAlias)
-		if err != nil {
-			return nil, fmt.Errorf("failed to check short URL uniqueness: %w", err)
-		}
-		if exists {
-			return nil, ErrAliasExists
+	// Если указан кастомный алиас, используем его
+	if req.CustomAlias != "" {
+		if err := uc.checkAlias(ctx, req.CustomAlias); err != nil {
+			return nil, uc.aliasTaken(ctx, req.CustomAlias, err)
 		}
 
 		shortURL = req.CustomAlias
@@ -100,10 +236,45 @@ func (uc *ShortenUseCase) Execute(ctx context.Context, req CreateLinkRequest) (*
 
 	// Создаём ссылку
 	link := &entity.Link{
-		ShortURL:    shortURL,
-		OriginalURL: req.OriginalURL,
-		CustomAlias: req.CustomAlias,
-		CreatedAt:   time.Now(),
+		ShortURL:     shortURL,
+		OriginalURL:  req.OriginalURL,
+		CanonicalURL: canonicalURL,
+		CustomAlias:  req.CustomAlias,
+		Owner:        req.Owner,
+		Campaign:     validated.campaign,
+		Tags:         validated.tags,
+		CreatedAt:    time.Now(),
+
+		RequireSignature:    req.RequireSignature,
+		InterstitialSeconds: req.InterstitialSeconds,
+		TargetingRules:      req.TargetingRules,
+		GeoRules:            req.GeoRules,
+		Rules:               req.Rules,
+		Variants:            req.Variants,
+		PrelaunchURL:        req.PrelaunchURL,
+		OpenGraph:           validated.openGraph,
+		DeepLink:            validated.deepLink,
+	}
+	// Границы окна хранятся в UTC, как и остальные даты в БД
+	if req.ActiveFrom != nil {
+		activeFrom := req.ActiveFrom.UTC()
+		link.ActiveFrom = &activeFrom
+	}
+	if req.ActiveUntil != nil {
+		activeUntil := req.ActiveUntil.UTC()
+		link.ActiveUntil = &activeUntil
+	}
+
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		link.PasswordHash = string(hash)
+		link.Protected = true
+	} else if req.passwordHash != "" {
+		link.PasswordHash = req.passwordHash
+		link.Protected = true
 	}
 
 	if err := uc.linkRepo.Create(ctx, link); err != nil {
@@ -115,14 +286,14 @@ func (uc *ShortenUseCase) Execute(ctx context.Context, req CreateLinkRequest) (*
 
 			// Если нарушено ограничение на custom_alias
 			if constraintName == "links_custom_alias_key" {
-				return nil, ErrAliasExists
+				return nil, uc.aliasTaken(ctx, req.CustomAlias, ErrAliasExists)
 			}
 
 			// Если нарушено ограничение на short_url
 			if constraintName == "links_short_url_key" {
 				// Если был указан custom_alias, это тоже ошибка алиаса (так как shortURL = customAlias)
 				if req.CustomAlias != "" {
-					return nil, ErrAliasExists
+					return nil, uc.aliasTaken(ctx, req.CustomAlias, ErrAliasExists)
 				}
 				// Если custom_alias не указан, это race condition - повторяем генерацию
 				// Но это не должно происходить, так как мы проверяем перед созданием
@@ -138,9 +309,8 @@ func (uc *ShortenUseCase) Execute(ctx context.Context, req CreateLinkRequest) (*
 
 	// Кэшируем ссылку
 	if uc.cache != nil {
-		cacheKey := fmt.Sprintf("link:%s", shortURL)
 		// Проверяем, что cache действительно не nil (для интерфейсов в Go)
-		if cacheErr := uc.cache.Set(ctx, cacheKey, link); cacheErr != nil {
+		if cacheErr := cacheLink(ctx, uc.cache, link); cacheErr != nil {
 			// Ошибка кэширования не критична, продолжаем работу
 			_ = cacheErr
 		}
@@ -151,3 +321,251 @@ func (uc *ShortenUseCase) Execute(ctx context.Context, req CreateLinkRequest) (*
 		OriginalURL: req.OriginalURL,
 	}, nil
 }
+
+// Validate проверяет запрос на создание ссылки, ничего не сохраняя
+func (uc *ShortenUseCase) Validate(ctx context.Context, req CreateLinkRequest) error {
+	validated, err := uc.validateRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if validated.customAlias != "" {
+		return uc.checkAlias(ctx, validated.customAlias)
+	}
+
+	return nil
+}
+
+// validatedRequest поля запроса на создание ссылки после проверки и нормализации
+type validatedRequest struct {
+	originalURL  string
+	canonicalURL string
+	customAlias  string
+	tags         []string
+	campaign     string
+	openGraph    *entity.OpenGraph
+	deepLink     *entity.DeepLink
+}
+
+// validateRequest проверяет запрос на создание ссылки и нормализует его поля.
+// Execute и Validate используют одну функцию, чтобы проверка без сохранения
+// не расходилась с созданием ссылки.
+func (uc *ShortenUseCase) validateRequest(ctx context.Context, req CreateLinkRequest) (*validatedRequest, error) {
+	if req.OriginalURL == "" {
+		return nil, ErrURLRequired
+	}
+	if err := ValidateURL(req.OriginalURL); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	// UTM-метки добавляются до проверок, чтобы проверялся итоговый адрес
+	originalURL, err := applyUTM(ctx, uc.utmTemplateRepo, req.OriginalURL, req.UTMTemplate, req.UTM)
+	if err != nil {
+		return nil, err
+	}
+	req.OriginalURL = originalURL
+
+	canonicalURL, err := uc.checkTarget(ctx, req.OriginalURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateLinkPassword(req.Password); err != nil {
+		return nil, err
+	}
+	if err := validatePasswordHash(req.passwordHash); err != nil {
+		return nil, err
+	}
+	if err := validateInterstitial(req.InterstitialSeconds); err != nil {
+		return nil, err
+	}
+	if err := uc.checkTargetingRules(ctx, req.TargetingRules); err != nil {
+		return nil, err
+	}
+	if err := uc.checkGeoRules(ctx, req.GeoRules); err != nil {
+		return nil, err
+	}
+	if err := uc.checkRoutingRules(ctx, req.Rules); err != nil {
+		return nil, err
+	}
+	if err := uc.checkVariants(ctx, req.Variants); err != nil {
+		return nil, err
+	}
+	if err := uc.checkActiveWindow(ctx, req); err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	campaign, err := uc.checkCampaign(ctx, req.Campaign)
+	if err != nil {
+		return nil, err
+	}
+	openGraph, err := normalizeOpenGraph(req.OpenGraph)
+	if err != nil {
+		return nil, err
+	}
+	deepLink, err := uc.checkDeepLink(ctx, req.DeepLink)
+	if err != nil {
+		return nil, err
+	}
+	customAlias, err := uc.normalizeAlias(req.CustomAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	return &validatedRequest{
+		originalURL:  req.OriginalURL,
+		canonicalURL: canonicalURL,
+		customAlias:  customAlias,
+		tags:         tags,
+		campaign:     campaign,
+		openGraph:    openGraph,
+		deepLink:     deepLink,
+	}, nil
+}
+
+// checkTarget нормализует URL и проверяет его по политике SSRF и правилам доменов.
+// Возвращает канонический вид URL.
+func (uc *ShortenUseCase) checkTarget(ctx context.Context, originalURL string) (string, error) {
+	canonicalURL, err := uc.normalizer.Normalize(originalURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	if err := uc.urlPolicy.Check(ctx, canonicalURL); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTargetNotAllowed, err)
+	}
+
+	if uc.domainRules != nil {
+		if err := uc.domainRules.Check(ctx, canonicalURL); err != nil {
+			return "", err
+		}
+	}
+
+	return canonicalURL, nil
+}
+
+// normalizeAlias проверяет кастомный алиас по политике алиасов и возвращает его
+// в форме для сохранения. Пустой алиас не проверяется.
+func (uc *ShortenUseCase) normalizeAlias(alias string) (string, error) {
+	if alias == "" {
+		return "", nil
+	}
+	normalized, err := uc.aliasPolicy.Normalize(alias)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidAlias, err)
+	}
+	return normalized, nil
+}
+
+// checkAlias проверяет, что кастомный алиас ещё не занят
+func (uc *ShortenUseCase) checkAlias(ctx context.Context, alias string) error {
+	// Проверяем, не занят ли алиас как custom_alias
+	existing, err := uc.linkRepo.GetByCustomAlias(ctx, alias)
+	if err != nil {
+		return fmt.Errorf("failed to check alias: %w", err)
+	}
+	if existing != nil {
+		return ErrAliasExists
+	}
+
+	// Проверяем, не занят ли алиас как short_url (так как shortURL = customAlias)
+	exists, err := uc.linkRepo.Exists(ctx, alias)
+	if err != nil {
+		return fmt.Errorf("failed to check short URL uniqueness: %w", err)
+	}
+	if exists {
+		return ErrAliasExists
+	}
+
+	return nil
+}
+
+// checkCampaign проверяет, что кампания существует, и возвращает её имя в нижнем регистре
+func (uc *ShortenUseCase) checkCampaign(ctx context.Context, name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || uc.campaignRepo == nil {
+		return name, nil
+	}
+
+	campaign, err := uc.campaignRepo.GetByName(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to check campaign: %w", err)
+	}
+	if campaign == nil {
+		return "", ErrCampaignNotFound
+	}
+	return name, nil
+}
+
+// validateLinkPassword проверяет длину пароля ссылки; пустой пароль означает ссылку без защиты
+func validateLinkPassword(password string) error {
+	if password == "" {
+		return nil
+	}
+	// bcrypt учитывает только первые 72 байта пароля
+	if len(password) < MinLinkPasswordLength || len(password) > MaxLinkPasswordLength {
+		return fmt.Errorf("%w: password must be %d to %d bytes long",
+			ErrInvalidLinkPassword, MinLinkPasswordLength, MaxLinkPasswordLength)
+	}
+	return nil
+}
+
+// validatePasswordHash проверяет, что перенесённый хэш пароля — хэш bcrypt
+func validatePasswordHash(hash string) error {
+	if hash == "" {
+		return nil
+	}
+	if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+		return fmt.Errorf("%w: password_hash is not a bcrypt hash", ErrInvalidLinkPassword)
+	}
+	return nil
+}
+
+// canReuse проверяет, допускает ли запрос переиспользование существующей ссылки.
+// Защищённые паролем ссылки, ссылки с правилами и окном активности не переиспользуются,
+// чтобы не смешивать доступы; ссылки с тегами и кампанией — чтобы не менять чужую группировку,
+// ссылки с UTM-метками — потому что нормализатор может не учитывать метки в каноническом URL.
+func canReuse(req CreateLinkRequest) bool {
+	return req.ReuseExisting && req.CustomAlias == "" && req.Password == "" && req.passwordHash == "" &&
+		len(req.TargetingRules) == 0 && len(req.GeoRules) == 0 && len(req.Rules) == 0 &&
+		len(req.Variants) == 0 && req.ActiveFrom == nil && req.ActiveUntil == nil &&
+		len(req.Tags) == 0 && req.Campaign == "" && req.UTMTemplate == "" && req.UTM == nil &&
+		req.OpenGraph == nil && req.DeepLink == nil
+}
+
+// isReusable проверяет, что существующая ссылка ведёт себя так же, как запрошенная
+func isReusable(link *entity.Link, req CreateLinkRequest) bool {
+	return !link.Protected &&
+		link.RequireSignature == req.RequireSignature &&
+		link.InterstitialSeconds == req.InterstitialSeconds &&
+		len(link.TargetingRules) == 0 &&
+		len(link.GeoRules) == 0 &&
+		len(link.Rules) == 0 &&
+		len(link.Variants) == 0 &&
+		link.ActiveFrom == nil &&
+		link.ActiveUntil == nil &&
+		link.OpenGraph == nil &&
+		link.DeepLink == nil
+}
+
+// validateInterstitial проверяет задержку промежуточной страницы
+func validateInterstitial(seconds int) error {
+	if seconds < 0 || seconds > MaxInterstitialSeconds {
+		return fmt.Errorf("%w: interstitial_seconds must be between 0 and %d",
+			ErrInvalidInterstitial, MaxInterstitialSeconds)
+	}
+	return nil
+}
+
+// hashRequest вычисляет отпечаток запроса для проверки повторного использования ключа
+func hashRequest(req CreateLinkRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
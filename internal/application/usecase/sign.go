@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/repository"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// SignLinkRequest запрос на подпись короткой ссылки.
+// Срок действия задаётся либо абсолютным временем expires_at, либо длительностью ttl.
+type SignLinkRequest struct {
+	ShortURL  string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	TTL       string    `json:"ttl,omitempty"`
+}
+
+// SignLinkResponse подписанная ссылка
+type SignLinkResponse struct {
+	ShortURL  string    `json:"short_url"`
+	ExpiresAt time.Time `json:"expires_at"`
+	KeyID     string    `json:"key_id"`
+}
+
+// SignUseCase выдаёт подписанные URL с ограниченным сроком действия
+type SignUseCase struct {
+	linkRepo         repository.LinkRepository
+	shortenerService *service.ShortenerService
+	signer           *service.URLSigner
+}
+
+// NewSignUseCase создаёт новый use case. Если signer равен nil, подпись отключена.
+func NewSignUseCase(
+	linkRepo repository.LinkRepository,
+	shortenerService *service.ShortenerService,
+	signer *service.URLSigner,
+) *SignUseCase {
+	return &SignUseCase{
+		linkRepo:         linkRepo,
+		shortenerService: shortenerService,
+		signer:           signer,
+	}
+}
+
+// Execute подписывает существующую ссылку
+func (uc *SignUseCase) Execute(ctx context.Context, req SignLinkRequest) (*SignLinkResponse, error) {
+	if uc.signer == nil {
+		return nil, ErrSigningDisabled
+	}
+
+	expiresAt, err := signatureExpiry(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := uc.linkRepo.GetByShortURL(ctx, req.ShortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+
+	return &SignLinkResponse{
+		ShortURL:  uc.signer.SignURL(uc.shortenerService.BuildShortURL(link.ShortURL), link.ShortURL, expiresAt),
+		ExpiresAt: expiresAt,
+		KeyID:     uc.signer.ActiveKey(),
+	}, nil
+}
+
+// signatureExpiry вычисляет время истечения подписи по запросу
+func signatureExpiry(req SignLinkRequest, now time.Time) (time.Time, error) {
+	var expiresAt time.Time
+	switch {
+	case req.TTL != "" && !req.ExpiresAt.IsZero():
+		return time.Time{}, fmt.Errorf("%w: specify either ttl or expires_at", ErrInvalidExpiry)
+	case req.TTL != "":
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidExpiry, err)
+		}
+		expiresAt = now.Add(ttl)
+	case !req.ExpiresAt.IsZero():
+		expiresAt = req.ExpiresAt
+	default:
+		expiresAt = now.Add(DefaultSignatureTTL)
+	}
+
+	// Подпись хранит время с точностью до секунды
+	expiresAt = expiresAt.UTC().Truncate(time.Second)
+	if !expiresAt.After(now) {
+		return time.Time{}, fmt.Errorf("%w: expiry must be in the future", ErrInvalidExpiry)
+	}
+	if expiresAt.Sub(now) > MaxSignatureTTL {
+		return time.Time{}, fmt.Errorf("%w: expiry is more than %s ahead", ErrInvalidExpiry, MaxSignatureTTL)
+	}
+	return expiresAt, nil
+}
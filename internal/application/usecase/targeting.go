@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// checkTargetingRules проверяет правила таргетинга: каждое правило должно иметь хотя бы одно
+// условие с известными значениями, а его URL проходит те же проверки, что и основной адрес
+func (uc *ShortenUseCase) checkTargetingRules(ctx context.Context, rules []entity.TargetingRule) error {
+	if len(rules) > MaxTargetingRules {
+		return fmt.Errorf("%w: at most %d rules are allowed", ErrInvalidTargetingRule, MaxTargetingRules)
+	}
+
+	for i, rule := range rules {
+		if len(rule.OS) == 0 && len(rule.DeviceType) == 0 && len(rule.Browser) == 0 {
+			return fmt.Errorf("%w: rule %d has no conditions", ErrInvalidTargetingRule, i)
+		}
+		if err := checkKnownValues(rule.OS, service.KnownOS); err != nil {
+			return fmt.Errorf("%w: rule %d: os: %v", ErrInvalidTargetingRule, i, err)
+		}
+		if err := checkKnownValues(rule.DeviceType, service.KnownDeviceTypes); err != nil {
+			return fmt.Errorf("%w: rule %d: device_type: %v", ErrInvalidTargetingRule, i, err)
+		}
+		if err := checkKnownValues(rule.Browser, service.KnownBrowsers); err != nil {
+			return fmt.Errorf("%w: rule %d: browser: %v", ErrInvalidTargetingRule, i, err)
+		}
+
+		if err := uc.checkRuleURL(ctx, rule.URL); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// checkRuleURL проверяет адрес назначения правила так же, как основной URL ссылки
+func (uc *ShortenUseCase) checkRuleURL(ctx context.Context, rawURL string) error {
+	if rawURL == "" {
+		return ErrURLRequired
+	}
+	if err := ValidateURL(rawURL); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	_, err := uc.checkTarget(ctx, rawURL)
+	return err
+}
+
+// checkKnownValues проверяет, что все значения входят в список допустимых
+func checkKnownValues(values, known []string) error {
+	for _, value := range values {
+		found := false
+		for _, k := range known {
+			if value == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown value %q", value)
+		}
+	}
+	return nil
+}
+
+// matchTargeting возвращает URL первого подходящего правила таргетинга и его идентификатор
+func matchTargeting(rules []entity.TargetingRule, userAgent string) (string, string, bool) {
+	if len(rules) == 0 {
+		return "", "", false
+	}
+
+	ua := service.ParseUserAgent(userAgent)
+	for i, rule := range rules {
+		if service.MatchTargetingRule(rule, ua) {
+			return rule.URL, "targeting:" + strconv.Itoa(i), true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lib/pq"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// UTMUseCase управляет шаблонами UTM-меток, собирает адреса с метками и строит отчёт по ним
+type UTMUseCase struct {
+	templateRepo repository.UTMTemplateRepository
+	clickRepo    repository.ClickRepository
+}
+
+// NewUTMUseCase создаёт новый use case
+func NewUTMUseCase(templateRepo repository.UTMTemplateRepository, clickRepo repository.ClickRepository) *UTMUseCase {
+	return &UTMUseCase{
+		templateRepo: templateRepo,
+		clickRepo:    clickRepo,
+	}
+}
+
+// UTMTemplateRequest запрос на создание или замену шаблона
+type UTMTemplateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	entity.UTMParams
+}
+
+// BuildUTMRequest запрос на сборку адреса с UTM-метками
+type BuildUTMRequest struct {
+	URL string `json:"url"`
+	// Template имя шаблона, метки которого используются по умолчанию
+	Template string `json:"template,omitempty"`
+	// UTM метки, заменяющие значения из шаблона и адреса
+	UTM *entity.UTMParams `json:"utm,omitempty"`
+}
+
+// BuildUTMResponse адрес с UTM-метками
+type BuildUTMResponse struct {
+	URL string           `json:"url"`
+	UTM entity.UTMParams `json:"utm"`
+}
+
+// UTMReport переходы, сгруппированные по значению UTM-метки
+type UTMReport struct {
+	GroupBy string                 `json:"group_by"`
+	Rows    []*entity.UTMReportRow `json:"rows"`
+}
+
+// CreateTemplate создаёт шаблон
+func (uc *UTMUseCase) CreateTemplate(ctx context.Context, req UTMTemplateRequest) (*entity.UTMTemplate, error) {
+	template, err := newUTMTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+	template.CreatedAt = time.Now()
+
+	if err := uc.templateRepo.Create(ctx, template); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrUTMTemplateExists
+		}
+		return nil, fmt.Errorf("failed to create utm template: %w", err)
+	}
+
+	return template, nil
+}
+
+// ListTemplates возвращает все шаблоны
+func (uc *UTMUseCase) ListTemplates(ctx context.Context) ([]*entity.UTMTemplate, error) {
+	templates, err := uc.templateRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list utm templates: %w", err)
+	}
+	if templates == nil {
+		templates = []*entity.UTMTemplate{}
+	}
+	return templates, nil
+}
+
+// GetTemplate возвращает шаблон по имени
+func (uc *UTMUseCase) GetTemplate(ctx context.Context, name string) (*entity.UTMTemplate, error) {
+	return getUTMTemplate(ctx, uc.templateRepo, name)
+}
+
+// UpdateTemplate заменяет описание и метки шаблона. Ссылки, уже созданные по шаблону,
+// не меняются: метки были добавлены в их адрес при создании.
+func (uc *UTMUseCase) UpdateTemplate(ctx context.Context, req UTMTemplateRequest) (*entity.UTMTemplate, error) {
+	template, err := newUTMTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.templateRepo.Update(ctx, template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update utm template: %w", err)
+	}
+	if !updated {
+		return nil, ErrUTMTemplateNotFound
+	}
+
+	return uc.GetTemplate(ctx, template.Name)
+}
+
+// DeleteTemplate удаляет шаблон
+func (uc *UTMUseCase) DeleteTemplate(ctx context.Context, name string) error {
+	deleted, err := uc.templateRepo.Delete(ctx, strings.ToLower(name))
+	if err != nil {
+		return fmt.Errorf("failed to delete utm template: %w", err)
+	}
+	if !deleted {
+		return ErrUTMTemplateNotFound
+	}
+	return nil
+}
+
+// Build добавляет UTM-метки в адрес так же, как при создании ссылки, ничего не сохраняя
+func (uc *UTMUseCase) Build(ctx context.Context, req BuildUTMRequest) (*BuildUTMResponse, error) {
+	if req.URL == "" {
+		return nil, ErrURLRequired
+	}
+	if err := ValidateURL(req.URL); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	result, err := applyUTM(ctx, uc.templateRepo, req.URL, req.Template, req.UTM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BuildUTMResponse{URL: result, UTM: service.ParseUTM(result)}, nil
+}
+
+// Report группирует переходы по значению метки groupBy: source, medium, campaign, term
+// или content. Значения берутся из адреса, на который был направлен посетитель.
+func (uc *UTMUseCase) Report(ctx context.Context, groupBy string) (*UTMReport, error) {
+	groupBy = strings.TrimPrefix(strings.ToLower(groupBy), "utm_")
+	if groupBy == "" {
+		groupBy = "campaign"
+	}
+	if !isUTMField(groupBy) {
+		return nil, fmt.Errorf("%w: group_by must be one of %s", ErrInvalidUTM, strings.Join(entity.UTMFields, ", "))
+	}
+
+	rows, err := uc.clickRepo.GetUTMReport(ctx, groupBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get utm report: %w", err)
+	}
+	if rows == nil {
+		rows = []*entity.UTMReportRow{}
+	}
+
+	return &UTMReport{GroupBy: groupBy, Rows: rows}, nil
+}
+
+// applyUTM добавляет в адрес метки шаблона templateName и метки overrides.
+// Метки шаблона не заменяют уже указанные в адресе, метки overrides заменяют любые.
+func applyUTM(ctx context.Context, templateRepo repository.UTMTemplateRepository, rawURL, templateName string, overrides *entity.UTMParams) (string, error) {
+	var defaults, explicit entity.UTMParams
+	if overrides != nil {
+		explicit = trimUTMParams(*overrides)
+		if err := validateUTMParams(explicit); err != nil {
+			return "", err
+		}
+	}
+	if templateName != "" {
+		if templateRepo == nil {
+			return "", ErrUTMTemplateNotFound
+		}
+		template, err := getUTMTemplate(ctx, templateRepo, templateName)
+		if err != nil {
+			return "", err
+		}
+		defaults = template.UTMParams
+	}
+	if defaults.IsZero() && explicit.IsZero() {
+		return rawURL, nil
+	}
+
+	result, err := service.ApplyUTM(rawURL, defaults, explicit)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	return result, nil
+}
+
+// clickUTM возвращает UTM-метки адреса назначения для записи перехода; nil — меток нет
+func clickUTM(rawURL string) *entity.UTMParams {
+	params := service.ParseUTM(rawURL)
+	if params.IsZero() {
+		return nil
+	}
+	// Колонки переходов ограничены по длине, метки из адресов правил не проверялись
+	if err := validateUTMParams(params); err != nil {
+		return nil
+	}
+	return &params
+}
+
+// getUTMTemplate возвращает шаблон по имени
+func getUTMTemplate(ctx context.Context, templateRepo repository.UTMTemplateRepository, name string) (*entity.UTMTemplate, error) {
+	template, err := templateRepo.GetByName(ctx, strings.ToLower(strings.TrimSpace(name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get utm template: %w", err)
+	}
+	if template == nil {
+		return nil, ErrUTMTemplateNotFound
+	}
+	return template, nil
+}
+
+// newUTMTemplate проверяет запрос и создаёт по нему шаблон
+func newUTMTemplate(req UTMTemplateRequest) (*entity.UTMTemplate, error) {
+	name, err := normalizeCatalogName(req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUTM, err)
+	}
+
+	params := trimUTMParams(req.UTMParams)
+	if params.IsZero() {
+		return nil, fmt.Errorf("%w: template must set at least one utm parameter", ErrInvalidUTM)
+	}
+	if err := validateUTMParams(params); err != nil {
+		return nil, err
+	}
+
+	return &entity.UTMTemplate{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		UTMParams:   params,
+	}, nil
+}
+
+// trimUTMParams убирает пробелы по краям значений меток
+func trimUTMParams(params entity.UTMParams) entity.UTMParams {
+	return entity.UTMParams{
+		Source:   strings.TrimSpace(params.Source),
+		Medium:   strings.TrimSpace(params.Medium),
+		Campaign: strings.TrimSpace(params.Campaign),
+		Term:     strings.TrimSpace(params.Term),
+		Content:  strings.TrimSpace(params.Content),
+	}
+}
+
+// validateUTMParams проверяет длину значений меток и отсутствие управляющих символов
+func validateUTMParams(params entity.UTMParams) error {
+	values := map[string]string{
+		"utm_source":   params.Source,
+		"utm_medium":   params.Medium,
+		"utm_campaign": params.Campaign,
+		"utm_term":     params.Term,
+		"utm_content":  params.Content,
+	}
+	for name, value := range values {
+		if len(value) > MaxUTMValueLength {
+			return fmt.Errorf("%w: %s must be at most %d bytes", ErrInvalidUTM, name, MaxUTMValueLength)
+		}
+		if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+			return fmt.Errorf("%w: %s must not contain control characters", ErrInvalidUTM, name)
+		}
+	}
+	return nil
+}
+
+// isUTMField проверяет, что field — имя UTM-метки без префикса
+func isUTMField(field string) bool {
+	for _, name := range entity.UTMFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"errors"
+	"net/url"
+)
+
+// ValidateURL проверяет валидность URL
+func ValidateURL(urlStr string) error {
+	if len(urlStr) > MaxURLLength {
+		return errors.New("URL too long")
+	}
+
+	parsedURL, err := url.Parse(urlStr)
+	if err != nil {
+		return err
+	}
+
+	if parsedURL.Scheme == "" {
+		return errors.New("URL scheme is required")
+	}
+
+	if parsedURL.Host == "" {
+		return errors.New("URL host is required")
+	}
+
+	// Проверяем что схема http или https
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return errors.New("URL scheme must be http or https")
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// variantNamePattern допустимые имена вариантов
+var variantNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// checkVariants проверяет варианты A/B-теста: уникальные имена, положительный суммарный вес
+// и адреса, проходящие те же проверки, что и основной URL
+func (uc *ShortenUseCase) checkVariants(ctx context.Context, variants []entity.Variant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+	if len(variants) < 2 || len(variants) > MaxVariants {
+		return fmt.Errorf("%w: between 2 and %d variants are required", ErrInvalidVariant, MaxVariants)
+	}
+
+	names := make(map[string]bool, len(variants))
+	totalWeight := 0
+	for i, variant := range variants {
+		if !variantNamePattern.MatchString(variant.Name) {
+			return fmt.Errorf("%w: variant %d: name must match %s", ErrInvalidVariant, i, variantNamePattern)
+		}
+		if names[variant.Name] {
+			return fmt.Errorf("%w: duplicate variant name %q", ErrInvalidVariant, variant.Name)
+		}
+		names[variant.Name] = true
+
+		if variant.Weight < 0 || variant.Weight > MaxVariantWeight {
+			return fmt.Errorf("%w: variant %q: weight must be between 0 and %d", ErrInvalidVariant, variant.Name, MaxVariantWeight)
+		}
+		totalWeight += variant.Weight
+
+		if err := uc.checkRuleURL(ctx, variant.URL); err != nil {
+			return fmt.Errorf("variant %q: %w", variant.Name, err)
+		}
+	}
+	if totalWeight == 0 {
+		return fmt.Errorf("%w: total weight must be positive", ErrInvalidVariant)
+	}
+
+	return nil
+}
+
+// pickVariant выбирает вариант по весам. Выбор детерминирован для пары посетитель и ссылка,
+// поэтому посетитель при повторных переходах попадает в тот же вариант.
+func pickVariant(variants []entity.Variant, visitorID, shortURL string) *entity.Variant {
+	totalWeight := 0
+	for _, variant := range variants {
+		totalWeight += variant.Weight
+	}
+	if totalWeight == 0 || visitorID == "" {
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(visitorID + "\n" + shortURL))
+	point := int(binary.BigEndian.Uint64(sum[:8]) % uint64(totalWeight))
+	for i := range variants {
+		point -= variants[i].Weight
+		if point < 0 {
+			return &variants[i]
+		}
+	}
+	return nil
+}
+
+// ConversionRequest запрос на регистрацию конверсии
+type ConversionRequest struct {
+	ShortURL  string
+	VisitorID string
+}
+
+// ConversionUseCase регистрирует конверсии для A/B-тестов
+type ConversionUseCase struct {
+	linkRepo       repository.LinkRepository
+	conversionRepo repository.ConversionRepository
+}
+
+// NewConversionUseCase создаёт новый use case
+func NewConversionUseCase(
+	linkRepo repository.LinkRepository,
+	conversionRepo repository.ConversionRepository,
+) *ConversionUseCase {
+	return &ConversionUseCase{
+		linkRepo:       linkRepo,
+		conversionRepo: conversionRepo,
+	}
+}
+
+// Execute сохраняет конверсию с вариантом, который был назначен посетителю при переходе
+func (uc *ConversionUseCase) Execute(ctx context.Context, req ConversionRequest) (*entity.Conversion, error) {
+	link, err := uc.linkRepo.GetByShortURL(ctx, req.ShortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+
+	conversion := &entity.Conversion{
+		LinkID:    link.ID,
+		CreatedAt: time.Now(),
+	}
+	if variant := pickVariant(link.Variants, req.VisitorID, link.ShortURL); variant != nil {
+		conversion.Variant = variant.Name
+	}
+
+	if err := uc.conversionRepo.Create(ctx, conversion); err != nil {
+		return nil, fmt.Errorf("failed to create conversion: %w", err)
+	}
+	return conversion, nil
+}
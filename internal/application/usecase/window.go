@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// checkActiveWindow проверяет окно активности и адрес для переходов до запуска
+func (uc *ShortenUseCase) checkActiveWindow(ctx context.Context, req CreateLinkRequest) error {
+	if req.ActiveFrom != nil && req.ActiveUntil != nil && !req.ActiveUntil.After(*req.ActiveFrom) {
+		return fmt.Errorf("%w: active_until must be after active_from", ErrInvalidActiveWindow)
+	}
+	if req.ActiveUntil != nil && !req.ActiveUntil.After(time.Now()) {
+		return fmt.Errorf("%w: active_until must be in the future", ErrInvalidActiveWindow)
+	}
+
+	if req.PrelaunchURL != "" {
+		if req.ActiveFrom == nil {
+			return fmt.Errorf("%w: prelaunch_url requires active_from", ErrInvalidActiveWindow)
+		}
+		if err := uc.checkRuleURL(ctx, req.PrelaunchURL); err != nil {
+			return fmt.Errorf("prelaunch_url: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// linkCacheTTL возвращает время жизни записи кэша для ссылки с окном активности,
+// чтобы запись истекала не позже ближайшей границы окна. ok = false — границ впереди нет.
+func linkCacheTTL(link *entity.Link, now time.Time) (time.Duration, bool) {
+	var boundary *time.Time
+	switch {
+	case link.IsPending(now):
+		boundary = link.ActiveFrom
+	case link.ActiveUntil != nil && !link.IsExpired(now):
+		boundary = link.ActiveUntil
+	default:
+		return 0, false
+	}
+
+	ttl := boundary.Sub(now)
+	if ttl > MaxWindowCacheTTL {
+		ttl = MaxWindowCacheTTL
+	}
+	// Нулевой TTL в Redis означает запись без срока действия
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	return ttl, true
+}
+
+// cacheLink сохраняет ссылку в кэш; запись ссылки с окном активности истекает на границе окна
+func cacheLink(ctx context.Context, cache Cache, link *entity.Link) error {
+	cacheKey := linkCacheKey(link.ShortURL)
+	if ttl, ok := linkCacheTTL(link, time.Now()); ok {
+		return cache.SetWithTTL(ctx, cacheKey, link, ttl)
+	}
+	return cache.Set(ctx, cacheKey, link)
+}
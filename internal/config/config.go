@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -17,6 +18,79 @@ type Config struct {
 	BaseURL       string
 	EnableRedis   bool
 	RedisTTL      time.Duration
+
+	// Нормализация URL перед сохранением
+	NormalizeSortQuery   bool
+	NormalizeStripParams []string
+
+	// Защита от SSRF: запрет ссылок на внутренние сети
+	SSRFBlockPrivate bool
+	SSRFResolveHosts bool
+
+	// AdminToken токен административного API; пустое значение отключает API
+	AdminToken string
+
+	// Правила блокировки и разрешения доменов
+	DomainRulesOnRedirect bool
+	DomainAllowlistOnly   bool
+	DomainRulesRefresh    time.Duration
+
+	// Ограничение частоты запросов
+	RateLimitEnabled   bool
+	RateLimitCreate    RateLimit
+	RateLimitAnalytics RateLimit
+	RateLimitRedirect  RateLimit
+	RateLimitPassword  RateLimit
+
+	// TrustedProxies CIDR прокси, которым доверяется заголовок с адресом клиента
+	TrustedProxies []string
+	// TrustedProxyHeader заголовок, который пишут доверенные прокси:
+	// x-forwarded-for, forwarded или x-real-ip
+	TrustedProxyHeader string
+
+	// Защищённые паролем ссылки: секрет подписи cookie и срок действия подтверждения
+	LinkCookieSecret string
+	LinkUnlockTTL    time.Duration
+
+	// Подписанные URL: ключи в формате "kid:secret" и идентификатор ключа для новых подписей
+	SigningKeys      []string
+	SigningActiveKey string
+
+	// QRLogoFile путь к логотипу (PNG или JPEG) для наложения на QR-коды
+	QRLogoFile string
+
+	// GeoIPFile путь к CSV-таблице сетей для гео-таргетинга
+	GeoIPFile string
+
+	// Фоновая проверка доступности адресов ссылок
+	HealthCheckEnabled     bool
+	HealthCheckInterval    time.Duration
+	HealthCheckConcurrency int
+	HealthCheckHostDelay   time.Duration
+	HealthCheckTimeout     time.Duration
+
+	// Загрузка метаданных Open Graph с адреса назначения
+	OpenGraphFetchEnabled bool
+	OpenGraphFetchTimeout time.Duration
+
+	// Файлы ассоциации домена с приложениями iOS и Android для /.well-known/
+	AppleAppSiteAssociationFile string
+	AssetLinksFile              string
+
+	// Политика кастомных алиасов: длина, приведение к нижнему регистру,
+	// дополнительные зарезервированные слова и файл со списком оскорбительных слов
+	AliasMinLength     int
+	AliasMaxLength     int
+	AliasLowercase     bool
+	AliasReserved      []string
+	AliasProfanityFile string
+}
+
+// RateLimit лимит запросов: Limit запросов за Period с допустимым всплеском Burst
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+	Burst  int
 }
 
 // Load загружает конфигурацию из переменных окружения и .env файла
@@ -33,6 +107,54 @@ func Load() (*Config, error) {
 		BaseURL:       getEnv("BASE_URL", "http://localhost:8080"),
 		EnableRedis:   getEnvBool("ENABLE_REDIS", false),
 		RedisTTL:      getEnvDuration("REDIS_TTL", 30*time.Minute),
+
+		NormalizeSortQuery:   getEnvBool("NORMALIZE_SORT_QUERY", false),
+		NormalizeStripParams: getEnvList("NORMALIZE_STRIP_PARAMS", []string{"utm_*", "fbclid", "gclid", "yclid", "mc_cid", "mc_eid"}),
+
+		SSRFBlockPrivate: getEnvBool("SSRF_BLOCK_PRIVATE", true),
+		SSRFResolveHosts: getEnvBool("SSRF_RESOLVE_HOSTS", false),
+
+		AdminToken: getEnv("ADMIN_TOKEN", ""),
+
+		DomainRulesOnRedirect: getEnvBool("DOMAIN_RULES_ON_REDIRECT", false),
+		DomainAllowlistOnly:   getEnvBool("DOMAIN_ALLOWLIST_ONLY", false),
+		DomainRulesRefresh:    getEnvDuration("DOMAIN_RULES_REFRESH", time.Minute),
+
+		RateLimitEnabled:   getEnvBool("RATE_LIMIT_ENABLED", true),
+		RateLimitCreate:    getEnvRateLimit("RATE_LIMIT_CREATE", RateLimit{Limit: 60, Period: time.Minute, Burst: 20}),
+		RateLimitAnalytics: getEnvRateLimit("RATE_LIMIT_ANALYTICS", RateLimit{Limit: 120, Period: time.Minute, Burst: 60}),
+		RateLimitRedirect:  getEnvRateLimit("RATE_LIMIT_REDIRECT", RateLimit{Limit: 600, Period: time.Minute, Burst: 200}),
+		RateLimitPassword:  getEnvRateLimit("RATE_LIMIT_PASSWORD", RateLimit{Limit: 5, Period: 15 * time.Minute, Burst: 5}),
+
+		TrustedProxies:     getEnvList("TRUSTED_PROXIES", nil),
+		TrustedProxyHeader: getEnv("TRUSTED_PROXY_HEADER", "x-forwarded-for"),
+
+		LinkCookieSecret: getEnv("LINK_COOKIE_SECRET", ""),
+		LinkUnlockTTL:    getEnvDuration("LINK_UNLOCK_TTL", time.Hour),
+
+		SigningKeys:      getEnvList("SIGNING_KEYS", nil),
+		SigningActiveKey: getEnv("SIGNING_ACTIVE_KEY", ""),
+
+		QRLogoFile: getEnv("QR_LOGO_FILE", ""),
+		GeoIPFile:  getEnv("GEOIP_FILE", ""),
+
+		HealthCheckEnabled:     getEnvBool("HEALTH_CHECK_ENABLED", false),
+		HealthCheckInterval:    getEnvDuration("HEALTH_CHECK_INTERVAL", 6*time.Hour),
+		HealthCheckConcurrency: getEnvInt("HEALTH_CHECK_CONCURRENCY", 8),
+		HealthCheckHostDelay:   getEnvDuration("HEALTH_CHECK_HOST_DELAY", 2*time.Second),
+		HealthCheckTimeout:     getEnvDuration("HEALTH_CHECK_TIMEOUT", 10*time.Second),
+
+		OpenGraphFetchEnabled: getEnvBool("OPEN_GRAPH_FETCH_ENABLED", false),
+		OpenGraphFetchTimeout: getEnvDuration("OPEN_GRAPH_FETCH_TIMEOUT", 10*time.Second),
+
+		AppleAppSiteAssociationFile: getEnv("APPLE_APP_SITE_ASSOCIATION_FILE", ""),
+		AssetLinksFile:              getEnv("ASSET_LINKS_FILE", ""),
+
+		AliasMinLength:     getEnvInt("ALIAS_MIN_LENGTH", 3),
+		AliasMaxLength:     getEnvInt("ALIAS_MAX_LENGTH", 64),
+		AliasLowercase:     getEnvBool("ALIAS_LOWERCASE", false),
+		AliasReserved:      getEnvList("ALIAS_RESERVED", nil),
+		AliasProfanityFile: getEnv("ALIAS_PROFANITY_FILE", ""),
 	}
 
 	return cfg, nil
@@ -59,6 +181,19 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return result
 }
 
+// getEnvInt получает целочисленную переменную окружения или возвращает значение по умолчанию
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue
+	}
+	return result
+}
+
 // getEnvDuration получает переменную окружения как duration или возвращает значение по умолчанию
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	value := os.Getenv(key)
@@ -71,3 +206,44 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return duration
 }
+
+// getEnvList получает список значений, разделённых запятыми, или возвращает значение по умолчанию
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// getEnvRateLimit получает лимит в формате "60/1m" из переменной key
+// и всплеск из переменной key_BURST, или возвращает значение по умолчанию.
+// Нулевые и отрицательные значения игнорируются.
+func getEnvRateLimit(key string, defaultValue RateLimit) RateLimit {
+	result := defaultValue
+
+	if value := os.Getenv(key); value != "" {
+		limit, period, ok := strings.Cut(value, "/")
+		parsedLimit, limitErr := strconv.Atoi(strings.TrimSpace(limit))
+		parsedPeriod, periodErr := time.ParseDuration(strings.TrimSpace(period))
+		if ok && limitErr == nil && periodErr == nil && parsedLimit > 0 && parsedPeriod > 0 {
+			result.Limit = parsedLimit
+			result.Period = parsedPeriod
+			result.Burst = parsedLimit
+		}
+	}
+
+	if value := os.Getenv(key + "_BURST"); value != "" {
+		if burst, err := strconv.Atoi(value); err == nil && burst > 0 {
+			result.Burst = burst
+		}
+	}
+
+	return result
+}
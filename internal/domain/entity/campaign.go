@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// Campaign кампания (папка), объединяющая ссылки
+type Campaign struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// LinkCount число ссылок в кампании
+	LinkCount int64     `json:"link_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Tag тег ссылок
+type Tag struct {
+	Name string `json:"name"`
+	// LinkCount число ссылок с тегом
+	LinkCount int64 `json:"link_count"`
+}
+
+// CampaignAnalytics сводная аналитика по всем ссылкам кампании
+type CampaignAnalytics struct {
+	Campaign         string           `json:"campaign"`
+	Links            int64            `json:"links"`
+	TotalClicks      int64            `json:"total_clicks"`
+	TotalConversions int64            `json:"total_conversions"`
+	ByDay            map[string]int64 `json:"by_day"`
+	// ByLink переходы по ссылкам кампании
+	ByLink map[string]int64 `json:"by_link"`
+}
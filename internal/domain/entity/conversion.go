@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Conversion представляет конверсию, зафиксированную после перехода по ссылке
+type Conversion struct {
+	ID     int64 `json:"id"`
+	LinkID int64 `json:"link_id"`
+	// Variant вариант A/B-теста, который видел посетитель; пусто — ссылка без вариантов
+	Variant   string    `json:"variant,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// VariantStats статистика варианта A/B-теста
+type VariantStats struct {
+	Clicks      int64 `json:"clicks"`
+	Conversions int64 `json:"conversions"`
+	// ConversionRate доля конверсий от переходов
+	ConversionRate float64 `json:"conversion_rate"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+// DeepLink адреса для открытия ссылки в мобильном приложении. Мобильные клиенты получают
+// страницу, которая пытается открыть приложение и, если оно не установлено, переходит
+// в магазин приложений или на веб-адрес.
+type DeepLink struct {
+	// AppURL адрес в приложении: своя схема (myapp://product/42) или universal link
+	AppURL string `json:"app_url"`
+	// IOSURL и AndroidURL заменяют AppURL на соответствующей платформе
+	IOSURL     string `json:"ios_url,omitempty"`
+	AndroidURL string `json:"android_url,omitempty"`
+	// IOSStoreURL и AndroidStoreURL адреса приложения в App Store и Google Play
+	IOSStoreURL     string `json:"ios_store_url,omitempty"`
+	AndroidStoreURL string `json:"android_store_url,omitempty"`
+	// FallbackURL веб-адрес, если приложение не открылось и адрес магазина не задан;
+	// по умолчанию используется адрес перехода
+	FallbackURL string `json:"fallback_url,omitempty"`
+}
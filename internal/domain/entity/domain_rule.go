@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// DomainRuleAction действие правила для доменов
+type DomainRuleAction string
+
+const (
+	// DomainRuleBlock запрещает ссылки на совпавшие адреса
+	DomainRuleBlock DomainRuleAction = "block"
+	// DomainRuleAllow разрешает ссылки на совпавшие адреса, имеет приоритет над block
+	DomainRuleAllow DomainRuleAction = "allow"
+)
+
+// DomainRuleKind способ сопоставления правила
+type DomainRuleKind string
+
+const (
+	// DomainRuleExact точное совпадение хоста
+	DomainRuleExact DomainRuleKind = "exact"
+	// DomainRuleWildcard любые поддомены: *.example.com
+	DomainRuleWildcard DomainRuleKind = "wildcard"
+	// DomainRuleRegex регулярное выражение по каноническому URL
+	DomainRuleRegex DomainRuleKind = "regex"
+	// DomainRuleHashPrefix hex-префикс SHA-256 выражения URL в стиле Safe Browsing
+	DomainRuleHashPrefix DomainRuleKind = "hash_prefix"
+)
+
+// DomainRule правило блокировки или разрешения целевых адресов
+type DomainRule struct {
+	ID        int64            `json:"id"`
+	Action    DomainRuleAction `json:"action"`
+	Kind      DomainRuleKind   `json:"kind"`
+	Pattern   string           `json:"pattern"`
+	Comment   string           `json:"comment,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
@@ -0,0 +1,69 @@
+package entity
+
+import "time"
+
+// Состояния доступности адреса ссылки
+const (
+	// HealthHealthy адрес отвечает
+	HealthHealthy = "healthy"
+	// HealthFailing последние проверки неудачны, но порог ещё не достигнут
+	HealthFailing = "failing"
+	// HealthBroken адрес не отвечает несколько проверок подряд
+	HealthBroken = "broken"
+)
+
+// События уведомлений о доступности адреса
+const (
+	HealthEventBroken    = "target_broken"
+	HealthEventRecovered = "target_recovered"
+)
+
+// LinkHealth текущее состояние доступности адреса ссылки
+type LinkHealth struct {
+	Status string `json:"status"`
+	// StatusCode код ответа последней проверки; 0 — запрос не выполнен
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// ConsecutiveFailures число неудачных проверок подряд
+	ConsecutiveFailures int       `json:"consecutive_failures,omitempty"`
+	CheckedAt           time.Time `json:"checked_at"`
+	// ChangedAt момент последней смены Status
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// HealthCheck результат одной проверки адреса
+type HealthCheck struct {
+	ID     int64 `json:"id"`
+	LinkID int64 `json:"-"`
+	// Healthy означает, что адрес ответил без ошибки
+	Healthy    bool   `json:"healthy"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Method     string `json:"method,omitempty"`
+	// Redirects цепочка адресов, по которым прошли редиректы
+	Redirects  []string  `json:"redirects,omitempty"`
+	FinalURL   string    `json:"final_url,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// HealthSubscription подписка владельца на уведомления о недоступных адресах
+type HealthSubscription struct {
+	ID int64 `json:"id"`
+	// Owner владелец ссылок; пусто — уведомления по всем ссылкам
+	Owner      string `json:"owner,omitempty"`
+	WebhookURL string `json:"webhook_url"`
+	// Secret ключ HMAC-подписи тела уведомления; не возвращается в API
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HealthAlert уведомление о смене доступности адреса ссылки
+type HealthAlert struct {
+	Event       string      `json:"event"`
+	ShortURL    string      `json:"short_url"`
+	OriginalURL string      `json:"original_url"`
+	Owner       string      `json:"owner,omitempty"`
+	Health      LinkHealth  `json:"health"`
+	Check       HealthCheck `json:"check"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// ImportStatus статус задачи импорта
+type ImportStatus string
+
+const (
+	// ImportStatusPending задача создана, но ещё не запущена
+	ImportStatusPending ImportStatus = "pending"
+	// ImportStatusRunning задача выполняется
+	ImportStatusRunning ImportStatus = "running"
+	// ImportStatusCompleted задача завершена
+	ImportStatusCompleted ImportStatus = "completed"
+	// ImportStatusFailed задача прервана из-за ошибки
+	ImportStatusFailed ImportStatus = "failed"
+)
+
+// ImportJob представляет задачу импорта ссылок
+type ImportJob struct {
+	ID         string           `json:"id"`
+	Status     ImportStatus     `json:"status"`
+	Format     string           `json:"format"`
+	DryRun     bool             `json:"dry_run"`
+	Total      int              `json:"total"`
+	Processed  int              `json:"processed"`
+	Succeeded  int              `json:"succeeded"`
+	Failed     int              `json:"failed"`
+	Error      string           `json:"error,omitempty"`
+	Errors     []ImportRowError `json:"errors,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+}
+
+// ImportRowError описывает ошибку в строке импорта
+type ImportRowError struct {
+	Row         int    `json:"row"`
+	OriginalURL string `json:"original_url,omitempty"`
+	CustomAlias string `json:"custom_alias,omitempty"`
+	Error       string `json:"error"`
+}
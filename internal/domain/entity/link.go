@@ -4,29 +4,122 @@ import "time"
 
 // Link представляет сокращённую ссылку
 type Link struct {
-	ID          int64     `json:"id"`
-	ShortURL    string    `json:"short_url"`
-	OriginalURL string    `json:"original_url"`
-	CustomAlias string    `json:"custom_alias,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int64  `json:"id"`
+	ShortURL    string `json:"short_url"`
+	OriginalURL string `json:"original_url"`
+	// CanonicalURL нормализованный URL для дедупликации и сопоставления с правилами
+	CanonicalURL string `json:"canonical_url,omitempty"`
+	CustomAlias  string `json:"custom_alias,omitempty"`
+	Owner        string `json:"owner,omitempty"`
+	// Campaign имя кампании, в которую входит ссылка
+	Campaign string `json:"campaign,omitempty"`
+	// Tags теги ссылки в алфавитном порядке
+	Tags []string `json:"tags,omitempty"`
+	// Protected означает, что для перехода нужен пароль
+	Protected bool `json:"protected,omitempty"`
+	// PasswordHash bcrypt-хэш пароля; не сериализуется, поэтому не попадает ни в API, ни в кэш
+	PasswordHash string `json:"-"`
+	// RequireSignature разрешает переход только по подписанному URL с exp и sig
+	RequireSignature bool `json:"require_signature,omitempty"`
+	// InterstitialSeconds задержка промежуточной страницы перед редиректом; 0 — редирект сразу
+	InterstitialSeconds int `json:"interstitial_seconds,omitempty"`
+	// TargetingRules правила выбора адреса по устройству; проверяются по порядку,
+	// если ни одно не подошло, используется OriginalURL
+	TargetingRules []TargetingRule `json:"targeting_rules,omitempty"`
+	// GeoRules правила выбора адреса по стране или континенту клиента
+	GeoRules []GeoRule `json:"geo_rules,omitempty"`
+	// Rules правила маршрутизации с условиями на заголовки, язык и расписание
+	Rules []RoutingRule `json:"rules,omitempty"`
+	// Variants варианты A/B-теста; если правила не сработали, адрес выбирается среди них по весам
+	Variants []Variant `json:"variants,omitempty"`
+	// ActiveFrom и ActiveUntil окно, в котором ссылка работает; nil — без ограничения
+	ActiveFrom  *time.Time `json:"active_from,omitempty"`
+	ActiveUntil *time.Time `json:"active_until,omitempty"`
+	// PrelaunchURL адрес для переходов до ActiveFrom; если не задан, показывается страница «скоро»
+	PrelaunchURL string `json:"prelaunch_url,omitempty"`
+	// OpenGraph превью для соцсетей, заданное вручную; заменяет поля FetchedOpenGraph
+	OpenGraph *OpenGraph `json:"open_graph,omitempty"`
+	// FetchedOpenGraph метаданные Open Graph, загруженные с адреса назначения
+	FetchedOpenGraph *OpenGraph `json:"fetched_open_graph,omitempty"`
+	// DeepLink адреса для открытия ссылки в мобильном приложении
+	DeepLink *DeepLink `json:"deep_link,omitempty"`
+	// Revision номер текущей ревизии ссылки в истории изменений
+	Revision int `json:"revision,omitempty"`
+	// Health доступность OriginalURL по данным фоновой проверки; nil — ещё не проверялся
+	Health    *LinkHealth `json:"health,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// IsPending проверяет, что окно активности ссылки ещё не открылось
+func (l *Link) IsPending(now time.Time) bool {
+	return l.ActiveFrom != nil && now.Before(*l.ActiveFrom)
+}
+
+// IsExpired проверяет, что окно активности ссылки уже закрылось
+func (l *Link) IsExpired(now time.Time) bool {
+	return l.ActiveUntil != nil && !now.Before(*l.ActiveUntil)
+}
+
+// TargetingRule правило таргетинга по атрибутам User-Agent.
+// Условия по разным атрибутам объединяются через И, значения внутри списка — через ИЛИ.
+type TargetingRule struct {
+	OS         []string `json:"os,omitempty"`
+	DeviceType []string `json:"device_type,omitempty"`
+	Browser    []string `json:"browser,omitempty"`
+	URL        string   `json:"url"`
+}
+
+// GeoRule правило выбора адреса по местоположению клиента.
+// Правило срабатывает, если страна или континент клиента входит в соответствующий список.
+type GeoRule struct {
+	Countries  []string `json:"countries,omitempty"`
+	Continents []string `json:"continents,omitempty"`
+	URL        string   `json:"url"`
+}
+
+// Variant вариант адреса в A/B-тесте
+type Variant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
 }
 
 // Click представляет переход по ссылке
 type Click struct {
-	ID        int64     `json:"id"`
-	LinkID    int64     `json:"link_id"`
-	UserAgent string    `json:"user_agent"`
-	IPAddress string    `json:"ip_address"`
+	ID        int64  `json:"id"`
+	LinkID    int64  `json:"link_id"`
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+	// MatchedRule идентификатор сработавшего правила (например, "targeting:0"); пусто — основной адрес
+	MatchedRule string `json:"matched_rule,omitempty"`
+	// Country код страны клиента, если определён по GeoIP
+	Country string `json:"country,omitempty"`
+	// Variant вариант A/B-теста, на который направлен посетитель
+	Variant string `json:"variant,omitempty"`
+	// Revision ревизия ссылки, действовавшая в момент перехода
+	Revision int `json:"revision,omitempty"`
+	// UTM метки адреса, на который направлен посетитель
+	UTM *UTMParams `json:"utm,omitempty"`
+	// PageID страница, с которой пришёл посетитель; для просмотра страницы LinkID равен 0
+	PageID    int64     `json:"page_id,omitempty"`
 	ClickedAt time.Time `json:"clicked_at"`
 }
 
 // Analytics представляет аналитику по ссылке
 type Analytics struct {
-	LinkID       int64            `json:"link_id"`
-	ShortURL     string           `json:"short_url"`
-	TotalClicks  int64            `json:"total_clicks"`
-	ByDay        map[string]int64 `json:"by_day"`
-	ByMonth      map[string]int64 `json:"by_month"`
-	ByUserAgent  map[string]int64 `json:"by_user_agent"`
-	RecentClicks []Click          `json:"recent_clicks,omitempty"`
+	LinkID      int64  `json:"link_id"`
+	ShortURL    string `json:"short_url"`
+	TotalClicks int64  `json:"total_clicks"`
+	// TotalConversions число конверсий, зафиксированных через /convert
+	TotalConversions int64            `json:"total_conversions"`
+	ByDay            map[string]int64 `json:"by_day"`
+	ByMonth          map[string]int64 `json:"by_month"`
+	ByUserAgent      map[string]int64 `json:"by_user_agent"`
+	// ByVariant переходы и конверсии по вариантам A/B-теста
+	ByVariant map[string]*VariantStats `json:"by_variant,omitempty"`
+	// ByRevision переходы по ревизиям ссылки
+	ByRevision map[int]int64 `json:"by_revision,omitempty"`
+	// ByUTMCampaign переходы по значению utm_campaign адреса назначения
+	ByUTMCampaign map[string]int64 `json:"by_utm_campaign,omitempty"`
+	RecentClicks  []Click          `json:"recent_clicks,omitempty"`
 }
@@ -0,0 +1,44 @@
+package entity
+
+// OpenGraph метаданные превью ссылки в соцсетях и мессенджерах
+type OpenGraph struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Image абсолютный адрес изображения http(s)
+	Image string `json:"image,omitempty"`
+}
+
+// IsZero проверяет, что ни одно поле не задано
+func (og OpenGraph) IsZero() bool {
+	return og == OpenGraph{}
+}
+
+// Merge возвращает метаданные og, дополненные непустыми значениями из override
+func (og OpenGraph) Merge(override OpenGraph) OpenGraph {
+	if override.Title != "" {
+		og.Title = override.Title
+	}
+	if override.Description != "" {
+		og.Description = override.Description
+	}
+	if override.Image != "" {
+		og.Image = override.Image
+	}
+	return og
+}
+
+// SocialPreview возвращает метаданные превью ссылки: загруженные с адреса назначения
+// с заменой полей, заданных вручную. nil — метаданных нет.
+func (l *Link) SocialPreview() *OpenGraph {
+	var og OpenGraph
+	if l.FetchedOpenGraph != nil {
+		og = *l.FetchedOpenGraph
+	}
+	if l.OpenGraph != nil {
+		og = og.Merge(*l.OpenGraph)
+	}
+	if og.IsZero() {
+		return nil
+	}
+	return &og
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// Page страница со списком ссылок, доступная по адресу /p/{slug}
+type Page struct {
+	ID          int64  `json:"id"`
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Items ссылки страницы в порядке отображения
+	Items     []PageItem `json:"items"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// PageItem пункт страницы, ссылающийся на короткую ссылку
+type PageItem struct {
+	ShortURL string `json:"short_url"`
+	Title    string `json:"title"`
+	// Icon эмодзи или адрес изображения (http/https)
+	Icon string `json:"icon,omitempty"`
+}
+
+// PageAnalytics просмотры страницы и переходы по её пунктам
+type PageAnalytics struct {
+	Slug       string `json:"slug"`
+	Views      int64  `json:"views"`
+	ItemClicks int64  `json:"item_clicks"`
+	// ViewsByDay просмотры по дням
+	ViewsByDay map[string]int64 `json:"views_by_day"`
+	// ByItem переходы со страницы по коротким ссылкам пунктов
+	ByItem map[string]int64 `json:"by_item"`
+}
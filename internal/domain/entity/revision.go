@@ -0,0 +1,74 @@
+package entity
+
+import "time"
+
+// Действия, которыми создаются ревизии ссылки
+const (
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionRollback = "rollback"
+)
+
+// LinkState изменяемые параметры ссылки, которые сохраняются в истории.
+// Пароль и требование подписи управляют доступом и в историю не входят.
+type LinkState struct {
+	OriginalURL         string          `json:"original_url"`
+	CanonicalURL        string          `json:"canonical_url"`
+	InterstitialSeconds int             `json:"interstitial_seconds,omitempty"`
+	TargetingRules      []TargetingRule `json:"targeting_rules,omitempty"`
+	GeoRules            []GeoRule       `json:"geo_rules,omitempty"`
+	Rules               []RoutingRule   `json:"rules,omitempty"`
+	Variants            []Variant       `json:"variants,omitempty"`
+	ActiveFrom          *time.Time      `json:"active_from,omitempty"`
+	ActiveUntil         *time.Time      `json:"active_until,omitempty"`
+	PrelaunchURL        string          `json:"prelaunch_url,omitempty"`
+	OpenGraph           *OpenGraph      `json:"open_graph,omitempty"`
+	DeepLink            *DeepLink       `json:"deep_link,omitempty"`
+}
+
+// LinkRevision версия ссылки в истории изменений
+type LinkRevision struct {
+	ID       int64 `json:"id"`
+	LinkID   int64 `json:"-"`
+	Revision int   `json:"revision"`
+	// Action действие, создавшее ревизию: create, update или rollback
+	Action string `json:"action"`
+	// RestoredFrom номер ревизии, восстановленной откатом
+	RestoredFrom int       `json:"restored_from,omitempty"`
+	State        LinkState `json:"state"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// State возвращает изменяемые параметры ссылки
+func (l *Link) State() LinkState {
+	return LinkState{
+		OriginalURL:         l.OriginalURL,
+		CanonicalURL:        l.CanonicalURL,
+		InterstitialSeconds: l.InterstitialSeconds,
+		TargetingRules:      l.TargetingRules,
+		GeoRules:            l.GeoRules,
+		Rules:               l.Rules,
+		Variants:            l.Variants,
+		ActiveFrom:          l.ActiveFrom,
+		ActiveUntil:         l.ActiveUntil,
+		PrelaunchURL:        l.PrelaunchURL,
+		OpenGraph:           l.OpenGraph,
+		DeepLink:            l.DeepLink,
+	}
+}
+
+// ApplyState заменяет изменяемые параметры ссылки
+func (l *Link) ApplyState(state LinkState) {
+	l.OriginalURL = state.OriginalURL
+	l.CanonicalURL = state.CanonicalURL
+	l.InterstitialSeconds = state.InterstitialSeconds
+	l.TargetingRules = state.TargetingRules
+	l.GeoRules = state.GeoRules
+	l.Rules = state.Rules
+	l.Variants = state.Variants
+	l.ActiveFrom = state.ActiveFrom
+	l.ActiveUntil = state.ActiveUntil
+	l.PrelaunchURL = state.PrelaunchURL
+	l.OpenGraph = state.OpenGraph
+	l.DeepLink = state.DeepLink
+}
@@ -0,0 +1,39 @@
+package entity
+
+// RoutingRule правило маршрутизации: если условие выполнено, клиент направляется на URL
+type RoutingRule struct {
+	Name string    `json:"name,omitempty"`
+	When Condition `json:"when"`
+	URL  string    `json:"url"`
+}
+
+// Condition узел выражения. В узле задаётся ровно одно поле: составное условие
+// (All — И, Any — ИЛИ) или одна из проверок.
+type Condition struct {
+	All      []Condition      `json:"all,omitempty"`
+	Any      []Condition      `json:"any,omitempty"`
+	Header   *HeaderCondition `json:"header,omitempty"`
+	Language []string         `json:"language,omitempty"`
+	Schedule *Schedule        `json:"schedule,omitempty"`
+}
+
+// HeaderCondition проверка заголовка запроса. Если не задано ни одно сравнение,
+// проверяется только наличие заголовка.
+type HeaderCondition struct {
+	Name string `json:"name"`
+	// Equals точное совпадение значения
+	Equals string `json:"equals,omitempty"`
+	// Contains вхождение подстроки без учёта регистра
+	Contains string `json:"contains,omitempty"`
+	// Prefix начало значения без учёта регистра
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// Schedule временное окно в часовом поясе. From и To задаются как "ЧЧ:ММ";
+// если From больше To, окно переходит через полночь.
+type Schedule struct {
+	Timezone string   `json:"timezone,omitempty"`
+	Days     []string `json:"days,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       string   `json:"to,omitempty"`
+}
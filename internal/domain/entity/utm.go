@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+// UTMFields имена UTM-меток без префикса utm_, по которым группируется аналитика
+var UTMFields = []string{"source", "medium", "campaign", "term", "content"}
+
+// UTMParams значения UTM-меток адреса назначения
+type UTMParams struct {
+	Source   string `json:"utm_source,omitempty"`
+	Medium   string `json:"utm_medium,omitempty"`
+	Campaign string `json:"utm_campaign,omitempty"`
+	Term     string `json:"utm_term,omitempty"`
+	Content  string `json:"utm_content,omitempty"`
+}
+
+// IsZero проверяет, что ни одна метка не задана
+func (p UTMParams) IsZero() bool {
+	return p == UTMParams{}
+}
+
+// Merge возвращает метки p, дополненные непустыми значениями из override
+func (p UTMParams) Merge(override UTMParams) UTMParams {
+	if override.Source != "" {
+		p.Source = override.Source
+	}
+	if override.Medium != "" {
+		p.Medium = override.Medium
+	}
+	if override.Campaign != "" {
+		p.Campaign = override.Campaign
+	}
+	if override.Term != "" {
+		p.Term = override.Term
+	}
+	if override.Content != "" {
+		p.Content = override.Content
+	}
+	return p
+}
+
+// UTMTemplate шаблон кампании со значениями UTM-меток по умолчанию
+type UTMTemplate struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	UTMParams
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UTMReportRow переходы по одному значению UTM-метки
+type UTMReportRow struct {
+	Value  string `json:"value"`
+	Links  int64  `json:"links"`
+	Clicks int64  `json:"clicks"`
+}
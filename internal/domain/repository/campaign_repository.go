@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// CampaignRepository определяет интерфейс для работы с кампаниями
+type CampaignRepository interface {
+	Create(ctx context.Context, campaign *entity.Campaign) error
+	GetByName(ctx context.Context, name string) (*entity.Campaign, error)
+	List(ctx context.Context) ([]*entity.Campaign, error)
+	// Update изменяет описание кампании; false — кампания не найдена
+	Update(ctx context.Context, campaign *entity.Campaign) (bool, error)
+	// Delete удаляет кампанию, ссылки остаются без кампании; false — кампания не найдена
+	Delete(ctx context.Context, name string) (bool, error)
+	// GetAnalytics суммирует переходы и конверсии по всем ссылкам кампании
+	GetAnalytics(ctx context.Context, name string) (*entity.CampaignAnalytics, error)
+}
+
+// TagRepository определяет интерфейс для работы с тегами.
+// Теги ссылок назначаются через LinkRepository.SetTags.
+type TagRepository interface {
+	// List возвращает теги с числом ссылок
+	List(ctx context.Context) ([]*entity.Tag, error)
+	// Delete снимает тег со всех ссылок и удаляет его; false — тег не найден
+	Delete(ctx context.Context, name string) (bool, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// ConversionRepository определяет интерфейс для работы с конверсиями
+type ConversionRepository interface {
+	Create(ctx context.Context, conversion *entity.Conversion) error
+	// CountByVariant возвращает число конверсий ссылки по вариантам
+	CountByVariant(ctx context.Context, linkID int64) (map[string]int64, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// DomainRuleRepository определяет интерфейс для работы с правилами доменов
+type DomainRuleRepository interface {
+	Create(ctx context.Context, rule *entity.DomainRule) error
+	Delete(ctx context.Context, id int64) (bool, error)
+	List(ctx context.Context) ([]*entity.DomainRule, error)
+}
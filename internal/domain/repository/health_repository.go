@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// HealthRepository определяет интерфейс для хранения проверок доступности ссылок
+type HealthRepository interface {
+	// ListDue возвращает действующие ссылки, которые не проверялись с момента before,
+	// начиная с давно не проверенных
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*entity.Link, error)
+	// SaveCheck записывает проверку в историю и обновляет состояние ссылки;
+	// в истории остаются последние keep проверок
+	SaveCheck(ctx context.Context, check *entity.HealthCheck, health *entity.LinkHealth, keep int) error
+	// ListChecks возвращает последние проверки ссылки, начиная с новой
+	ListChecks(ctx context.Context, linkID int64, limit int) ([]*entity.HealthCheck, error)
+}
+
+// HealthSubscriptionRepository определяет интерфейс для подписок на уведомления о доступности
+type HealthSubscriptionRepository interface {
+	Create(ctx context.Context, subscription *entity.HealthSubscription) error
+	List(ctx context.Context) ([]*entity.HealthSubscription, error)
+	// ListForOwner возвращает подписки владельца и подписки на все ссылки
+	ListForOwner(ctx context.Context, owner string) ([]*entity.HealthSubscription, error)
+	// Delete удаляет подписку; false — подписка не найдена
+	Delete(ctx context.Context, id int64) (bool, error)
+}
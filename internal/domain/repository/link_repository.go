@@ -6,12 +6,36 @@ import (
 	"github.com/oziev02/Shortener/internal/domain/entity"
 )
 
+// LinkFilter параметры выборки списка ссылок
+type LinkFilter struct {
+	Limit  int
+	Offset int
+	// Tag и Campaign ограничивают выборку ссылками с тегом и ссылками кампании
+	Tag      string
+	Campaign string
+}
+
 // LinkRepository определяет интерфейс для работы с ссылками
 type LinkRepository interface {
 	Create(ctx context.Context, link *entity.Link) error
 	GetByShortURL(ctx context.Context, shortURL string) (*entity.Link, error)
 	GetByCustomAlias(ctx context.Context, alias string) (*entity.Link, error)
 	Exists(ctx context.Context, shortURL string) (bool, error)
+	// TakenAliases возвращает те из aliases, которые уже заняты как short_url или custom_alias
+	TakenAliases(ctx context.Context, aliases []string) ([]string, error)
+	GetByOwnerAndCanonicalURL(ctx context.Context, owner, canonicalURL string) (*entity.Link, error)
+	List(ctx context.Context, filter LinkFilter) ([]*entity.Link, error)
+	// Update сохраняет изменяемые параметры ссылки и ревизию в одной транзакции.
+	// Обновление выполняется, только если link.Revision совпадает с текущей ревизией в БД;
+	// иначе возвращается false. При успехе link.Revision и revision.Revision увеличиваются.
+	Update(ctx context.Context, link *entity.Link, revision *entity.LinkRevision) (bool, error)
+	// SetTags заменяет теги ссылки; отсутствующие теги создаются
+	SetTags(ctx context.Context, linkID int64, tags []string) error
+	// SetCampaign переносит ссылку в кампанию; пустое имя убирает ссылку из кампании
+	SetCampaign(ctx context.Context, linkID int64, campaign string) error
+	// SetFetchedOpenGraph сохраняет метаданные Open Graph, загруженные с адреса назначения;
+	// nil удаляет их. Ревизия ссылки не меняется.
+	SetFetchedOpenGraph(ctx context.Context, linkID int64, og *entity.OpenGraph) error
 }
 
 // ClickRepository определяет интерфейс для работы с переходами
@@ -19,4 +43,6 @@ type ClickRepository interface {
 	Create(ctx context.Context, click *entity.Click) error
 	GetAnalytics(ctx context.Context, linkID int64) (*entity.Analytics, error)
 	GetByLinkID(ctx context.Context, linkID int64, limit int) ([]*entity.Click, error)
+	// GetUTMReport группирует переходы по значению UTM-метки field (entity.UTMFields)
+	GetUTMReport(ctx context.Context, field string) ([]*entity.UTMReportRow, error)
 }
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// LinkRevisionRepository определяет интерфейс для чтения истории изменений ссылок.
+// Ревизии записываются LinkRepository вместе с изменением ссылки.
+type LinkRevisionRepository interface {
+	// ListByLinkID возвращает ревизии ссылки, начиная с последней
+	ListByLinkID(ctx context.Context, linkID int64) ([]*entity.LinkRevision, error)
+	GetByRevision(ctx context.Context, linkID int64, revision int) (*entity.LinkRevision, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// PageRepository определяет интерфейс для работы со страницами ссылок
+type PageRepository interface {
+	// Create сохраняет страницу вместе с пунктами
+	Create(ctx context.Context, page *entity.Page) error
+	GetBySlug(ctx context.Context, slug string) (*entity.Page, error)
+	List(ctx context.Context) ([]*entity.Page, error)
+	// Update заменяет заголовок, описание и пункты страницы; false — страница не найдена
+	Update(ctx context.Context, page *entity.Page) (bool, error)
+	// Delete удаляет страницу и её просмотры; переходы по пунктам остаются в аналитике ссылок.
+	// false — страница не найдена
+	Delete(ctx context.Context, slug string) (bool, error)
+	// GetAnalytics возвращает просмотры страницы и переходы по её пунктам
+	GetAnalytics(ctx context.Context, pageID int64) (*entity.PageAnalytics, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// UTMTemplateRepository определяет интерфейс для работы с шаблонами UTM-меток
+type UTMTemplateRepository interface {
+	Create(ctx context.Context, template *entity.UTMTemplate) error
+	GetByName(ctx context.Context, name string) (*entity.UTMTemplate, error)
+	List(ctx context.Context) ([]*entity.UTMTemplate, error)
+	// Update заменяет описание и метки шаблона; false — шаблон не найден
+	Update(ctx context.Context, template *entity.UTMTemplate) (bool, error)
+	// Delete удаляет шаблон; созданные по нему ссылки не меняются. false — шаблон не найден
+	Delete(ctx context.Context, name string) (bool, error)
+}
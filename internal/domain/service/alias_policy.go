@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Причины отклонения алиаса
+var (
+	ErrAliasTooShort          = errors.New("alias is too short")
+	ErrAliasTooLong           = errors.New("alias is too long")
+	ErrAliasInvalidCharacters = errors.New("alias contains invalid characters")
+	ErrAliasReserved          = errors.New("alias is reserved")
+	ErrAliasProfane           = errors.New("alias contains offensive language")
+)
+
+// DefaultReservedAliases слова, которые нельзя использовать как алиас: служебные пути
+// и имена, которые пользователи могут принять за страницы сервиса. Пути маршрутов
+// добавляются к списку при создании политики.
+var DefaultReservedAliases = []string{
+	"admin", "administrator", "api", "app", "assets", "static", "public", "media", "images", "img",
+	"css", "js", "favicon", "robots", "sitemap", "well-known", "index", "home", "login", "logout",
+	"signin", "signout", "signup", "register", "auth", "oauth", "account", "accounts", "user", "users",
+	"settings", "dashboard", "console", "help", "support", "about", "contact", "terms", "privacy",
+	"legal", "security", "status", "docs", "documentation", "www", "mail", "root", "system", "null",
+	"undefined", "new", "edit", "delete", "preview",
+}
+
+// DefaultProfanity корни оскорбительных слов. Корни ищутся как подстроки, поэтому в список
+// входят только те, что почти не встречаются внутри обычных слов.
+var DefaultProfanity = []string{
+	"fuck", "shit", "cunt", "bitch", "asshole", "bastard", "whore", "slut", "faggot", "nigger",
+	"nigga", "retard", "motherf", "wanker", "twat",
+	"khuy", "huilo", "pizd", "blyad", "blyat", "ebal", "eblan", "ebat", "mudak", "pidor", "pidar",
+	"gandon", "zalupa", "shluh",
+}
+
+// aliasLeetReplacer заменяет цифры, которыми маскируют буквы, при поиске оскорбительных слов
+var aliasLeetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "8", "b")
+
+// AliasPolicy проверяет кастомные алиасы: допустимые символы, длину, зарезервированные
+// и оскорбительные слова. Зарезервированные и оскорбительные слова сравниваются без учёта
+// регистра, поэтому "Admin" отклоняется так же, как "admin".
+type AliasPolicy struct {
+	minLength int
+	maxLength int
+	lowercase bool
+	reserved  map[string]bool
+	profanity []string
+}
+
+// NewAliasPolicy создаёт политику. Алиас должен содержать от minLength до maxLength символов
+// a-z, A-Z, 0-9, "-" и "_". Если lowercase равен true, алиас приводится к нижнему регистру
+// перед сохранением.
+func NewAliasPolicy(minLength, maxLength int, lowercase bool, reserved, profanity []string) *AliasPolicy {
+	policy := &AliasPolicy{
+		minLength: minLength,
+		maxLength: maxLength,
+		lowercase: lowercase,
+		reserved:  make(map[string]bool, len(reserved)),
+	}
+	for _, word := range reserved {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			policy.reserved[word] = true
+		}
+	}
+	for _, word := range profanity {
+		if word = compactAlias(word); word != "" {
+			policy.profanity = append(policy.profanity, word)
+		}
+	}
+	return policy
+}
+
+// Normalize проверяет алиас и возвращает его в форме для сохранения
+func (p *AliasPolicy) Normalize(alias string) (string, error) {
+	if p == nil {
+		return alias, nil
+	}
+
+	length := len(alias)
+	if length < p.minLength {
+		return "", fmt.Errorf("%w: must be at least %d characters", ErrAliasTooShort, p.minLength)
+	}
+	if p.maxLength > 0 && length > p.maxLength {
+		return "", fmt.Errorf("%w: must be at most %d characters", ErrAliasTooLong, p.maxLength)
+	}
+	for i := 0; i < length; i++ {
+		if !isAliasChar(alias[i]) {
+			return "", fmt.Errorf("%w: only a-z, A-Z, 0-9, \"-\" and \"_\" are allowed", ErrAliasInvalidCharacters)
+		}
+	}
+
+	folded := strings.ToLower(alias)
+	if p.reserved[folded] {
+		return "", fmt.Errorf("%w: %q", ErrAliasReserved, folded)
+	}
+	compact := compactAlias(folded)
+	for _, word := range p.profanity {
+		if strings.Contains(compact, word) {
+			return "", ErrAliasProfane
+		}
+	}
+
+	if p.lowercase {
+		return folded, nil
+	}
+	return alias, nil
+}
+
+// MaxLength возвращает максимальную длину алиаса; 0 означает, что длина не ограничена
+func (p *AliasPolicy) MaxLength() int {
+	if p == nil {
+		return 0
+	}
+	return p.maxLength
+}
+
+// isAliasChar проверяет, что символ допустим в алиасе
+func isAliasChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+// compactAlias приводит строку к виду для поиска оскорбительных слов: нижний регистр,
+// цифры заменены похожими буквами, разделители удалены
+func compactAlias(value string) string {
+	value = aliasLeetReplacer.Replace(strings.ToLower(strings.TrimSpace(value)))
+	return strings.NewReplacer("-", "", "_", "").Replace(value)
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// defaultPorts порты по умолчанию, которые удаляются из канонического URL
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// URLNormalizer приводит URL к каноническому виду для дедупликации и сопоставления с правилами
+type URLNormalizer struct {
+	sortQuery   bool
+	stripExact  map[string]struct{}
+	stripPrefix []string
+}
+
+// NewURLNormalizer создаёт нормализатор.
+// stripParams — список удаляемых query-параметров; шаблон вида "utm_*" удаляет все параметры с префиксом.
+func NewURLNormalizer(sortQuery bool, stripParams []string) *URLNormalizer {
+	n := &URLNormalizer{
+		sortQuery:  sortQuery,
+		stripExact: make(map[string]struct{}),
+	}
+	for _, param := range stripParams {
+		param = strings.ToLower(strings.TrimSpace(param))
+		switch {
+		case param == "":
+		case strings.HasSuffix(param, "*"):
+			n.stripPrefix = append(n.stripPrefix, strings.TrimSuffix(param, "*"))
+		default:
+			n.stripExact[param] = struct{}{}
+		}
+	}
+	return n
+}
+
+// Normalize возвращает канонический вид URL: схема и хост в нижнем регистре,
+// IDN в punycode, без портов по умолчанию и dot-сегментов, без трекинговых параметров
+func (n *URLNormalizer) Normalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	host, err := n.normalizeHost(u.Scheme, u.Host)
+	if err != nil {
+		return "", err
+	}
+	u.Host = host
+
+	// Работаем с экранированным путём, чтобы не потерять закодированные символы вроде %2F
+	escapedPath := removeDotSegments(u.EscapedPath())
+	if escapedPath == "" {
+		escapedPath = "/"
+	}
+	unescapedPath, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		return "", err
+	}
+	u.Path, u.RawPath = unescapedPath, escapedPath
+
+	u.RawQuery = n.normalizeQuery(u.RawQuery)
+	if u.RawQuery == "" {
+		u.ForceQuery = false
+	}
+
+	return u.String(), nil
+}
+
+// normalizeHost приводит хост к нижнему регистру, переводит IDN в punycode и убирает порт по умолчанию
+func (n *URLNormalizer) normalizeHost(scheme, hostport string) (string, error) {
+	host, port := hostport, ""
+	if h, p, err := net.SplitHostPort(hostport); err == nil {
+		host, port = h, p
+	}
+	host, err := NormalizeHost(host)
+	if err != nil {
+		return "", err
+	}
+
+	if port != "" && port != defaultPorts[scheme] {
+		return net.JoinHostPort(host, port), nil
+	}
+	if strings.Contains(host, ":") {
+		return "[" + host + "]", nil
+	}
+	return host, nil
+}
+
+// NormalizeHost приводит имя хоста к нижнему регистру и переводит IDN в punycode.
+// IPv6-адрес возвращается без квадратных скобок.
+func NormalizeHost(host string) (string, error) {
+	host = strings.Trim(host, "[]")
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	// IP-адреса (в том числе IPv6) не проходят через IDNA
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", fmt.Errorf("invalid host %q: %w", host, err)
+	}
+	return ascii, nil
+}
+
+// normalizeQuery удаляет трекинговые параметры и при необходимости сортирует оставшиеся
+func (n *URLNormalizer) normalizeQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	var pairs []string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key := pair
+		if i := strings.IndexByte(pair, '='); i >= 0 {
+			key = pair[:i]
+		}
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if n.shouldStrip(key) {
+			continue
+		}
+		pairs = append(pairs, pair)
+	}
+
+	if n.sortQuery {
+		// Стабильная сортировка сохраняет порядок повторяющихся ключей
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return queryKey(pairs[i]) < queryKey(pairs[j])
+		})
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// shouldStrip проверяет, входит ли параметр в список удаляемых
+func (n *URLNormalizer) shouldStrip(key string) bool {
+	key = strings.ToLower(key)
+	if _, ok := n.stripExact[key]; ok {
+		return true
+	}
+	for _, prefix := range n.stripPrefix {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryKey возвращает имя параметра из пары key=value
+func queryKey(pair string) string {
+	if i := strings.IndexByte(pair, '='); i >= 0 {
+		return pair[:i]
+	}
+	return pair
+}
+
+// removeDotSegments удаляет сегменты "." и ".." из пути по алгоритму RFC 3986, раздел 5.2.4
+func removeDotSegments(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	var output []string
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch segment {
+		case ".":
+			if last {
+				output = append(output, "")
+			}
+		case "..":
+			// Первый элемент — пустой сегмент перед ведущим "/", его не удаляем
+			if len(output) > 1 {
+				output = output[:len(output)-1]
+			}
+			if last {
+				output = append(output, "")
+			}
+		default:
+			output = append(output, segment)
+		}
+	}
+
+	result := strings.Join(output, "/")
+	if strings.HasPrefix(path, "/") && !strings.HasPrefix(result, "/") {
+		result = "/" + result
+	}
+	return result
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+const (
+	// maxConditionDepth максимальная вложенность условий
+	maxConditionDepth = 5
+	// maxConditionNodes максимальное число узлов в условии одного правила
+	maxConditionNodes = 50
+)
+
+// weekdays сокращённые названия дней недели в правилах расписания
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// RuleContext данные запроса, по которым вычисляются условия
+type RuleContext struct {
+	Headers http.Header
+	Now     time.Time
+}
+
+// ConditionResult результат вычисления узла условия с объяснением
+type ConditionResult struct {
+	Condition string            `json:"condition"`
+	Matched   bool              `json:"matched"`
+	Reason    string            `json:"reason,omitempty"`
+	Children  []ConditionResult `json:"children,omitempty"`
+}
+
+// RuleEvaluator проверяет и вычисляет условия правил маршрутизации
+type RuleEvaluator struct {
+	// locations кэш загруженных часовых поясов
+	locations sync.Map
+}
+
+// NewRuleEvaluator создаёт вычислитель условий
+func NewRuleEvaluator() *RuleEvaluator {
+	return &RuleEvaluator{}
+}
+
+// Validate проверяет структуру условия: в каждом узле ровно одна проверка,
+// корректные часовые пояса, дни недели и время
+func (e *RuleEvaluator) Validate(cond entity.Condition) error {
+	nodes := 0
+	return e.validate(cond, 1, &nodes)
+}
+
+func (e *RuleEvaluator) validate(cond entity.Condition, depth int, nodes *int) error {
+	*nodes++
+	if *nodes > maxConditionNodes {
+		return fmt.Errorf("condition has more than %d nodes", maxConditionNodes)
+	}
+	if depth > maxConditionDepth {
+		return fmt.Errorf("condition is nested deeper than %d levels", maxConditionDepth)
+	}
+
+	set := 0
+	for _, present := range []bool{
+		cond.All != nil, cond.Any != nil, cond.Header != nil, cond.Language != nil, cond.Schedule != nil,
+	} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("each condition must have exactly one of all, any, header, language, schedule")
+	}
+
+	switch {
+	case cond.All != nil || cond.Any != nil:
+		children := cond.All
+		if cond.Any != nil {
+			children = cond.Any
+		}
+		if len(children) == 0 {
+			return errors.New("all/any must contain at least one condition")
+		}
+		for _, child := range children {
+			if err := e.validate(child, depth+1, nodes); err != nil {
+				return err
+			}
+		}
+	case cond.Header != nil:
+		if http.CanonicalHeaderKey(strings.TrimSpace(cond.Header.Name)) == "" {
+			return errors.New("header name is required")
+		}
+	case cond.Language != nil:
+		if len(cond.Language) == 0 {
+			return errors.New("language list must not be empty")
+		}
+		for _, tag := range cond.Language {
+			if tag == "" || strings.ContainsAny(tag, " ,;") {
+				return fmt.Errorf("invalid language tag %q", tag)
+			}
+		}
+	case cond.Schedule != nil:
+		return e.validateSchedule(cond.Schedule)
+	}
+	return nil
+}
+
+// validateSchedule проверяет часовой пояс, дни недели и границы окна
+func (e *RuleEvaluator) validateSchedule(schedule *entity.Schedule) error {
+	if _, err := e.location(schedule.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", schedule.Timezone)
+	}
+	for _, day := range schedule.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid day %q, expected mon..sun", day)
+		}
+	}
+	if (schedule.From == "") != (schedule.To == "") {
+		return errors.New("schedule from and to must be set together")
+	}
+	if schedule.From != "" {
+		if _, err := parseClock(schedule.From); err != nil {
+			return err
+		}
+		if _, err := parseClock(schedule.To); err != nil {
+			return err
+		}
+	}
+	if len(schedule.Days) == 0 && schedule.From == "" {
+		return errors.New("schedule must restrict days or time")
+	}
+	return nil
+}
+
+// Evaluate вычисляет условие и возвращает дерево с объяснением результата
+func (e *RuleEvaluator) Evaluate(cond entity.Condition, rc RuleContext) ConditionResult {
+	switch {
+	case cond.All != nil:
+		result := ConditionResult{Condition: "all", Matched: true}
+		for _, child := range cond.All {
+			childResult := e.Evaluate(child, rc)
+			result.Children = append(result.Children, childResult)
+			if !childResult.Matched {
+				result.Matched = false
+			}
+		}
+		result.Reason = fmt.Sprintf("%d of %d conditions matched", countMatched(result.Children), len(cond.All))
+		return result
+
+	case cond.Any != nil:
+		result := ConditionResult{Condition: "any"}
+		for _, child := range cond.Any {
+			childResult := e.Evaluate(child, rc)
+			result.Children = append(result.Children, childResult)
+			if childResult.Matched {
+				result.Matched = true
+			}
+		}
+		result.Reason = fmt.Sprintf("%d of %d conditions matched", countMatched(result.Children), len(cond.Any))
+		return result
+
+	case cond.Header != nil:
+		return evaluateHeader(cond.Header, rc.Headers)
+
+	case cond.Language != nil:
+		return evaluateLanguage(cond.Language, rc.Headers.Get("Accept-Language"))
+
+	case cond.Schedule != nil:
+		return e.evaluateSchedule(cond.Schedule, rc.Now)
+
+	default:
+		return ConditionResult{Condition: "empty", Reason: "condition has no checks"}
+	}
+}
+
+// evaluateHeader проверяет значение заголовка
+func evaluateHeader(cond *entity.HeaderCondition, headers http.Header) ConditionResult {
+	name := http.CanonicalHeaderKey(strings.TrimSpace(cond.Name))
+	result := ConditionResult{Condition: "header " + name}
+
+	values, ok := headers[name]
+	if !ok {
+		result.Reason = "header is missing"
+		return result
+	}
+	value := strings.Join(values, ", ")
+	lower := strings.ToLower(value)
+
+	switch {
+	case cond.Equals != "" && value != cond.Equals:
+		result.Reason = fmt.Sprintf("value %q is not equal to %q", value, cond.Equals)
+	case cond.Contains != "" && !strings.Contains(lower, strings.ToLower(cond.Contains)):
+		result.Reason = fmt.Sprintf("value %q does not contain %q", value, cond.Contains)
+	case cond.Prefix != "" && !strings.HasPrefix(lower, strings.ToLower(cond.Prefix)):
+		result.Reason = fmt.Sprintf("value %q does not start with %q", value, cond.Prefix)
+	default:
+		result.Matched = true
+		result.Reason = fmt.Sprintf("value %q matches", value)
+	}
+	return result
+}
+
+// evaluateLanguage сравнивает предпочтительный язык клиента со списком.
+// Тег из списка совпадает с самим собой и с уточнёнными тегами: "en" подходит для "en-US".
+func evaluateLanguage(tags []string, acceptLanguage string) ConditionResult {
+	result := ConditionResult{Condition: "language " + strings.Join(tags, ",")}
+
+	preferred := PreferredLanguage(acceptLanguage)
+	if preferred == "" {
+		result.Reason = "Accept-Language is missing"
+		return result
+	}
+
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		if preferred == tag || strings.HasPrefix(preferred, tag+"-") {
+			result.Matched = true
+			result.Reason = fmt.Sprintf("preferred language %q matches %q", preferred, tag)
+			return result
+		}
+	}
+	result.Reason = fmt.Sprintf("preferred language %q is not in the list", preferred)
+	return result
+}
+
+// evaluateSchedule проверяет, попадает ли момент времени в окно расписания
+func (e *RuleEvaluator) evaluateSchedule(schedule *entity.Schedule, now time.Time) ConditionResult {
+	result := ConditionResult{Condition: "schedule"}
+
+	loc, err := e.location(schedule.Timezone)
+	if err != nil {
+		result.Reason = fmt.Sprintf("invalid timezone %q", schedule.Timezone)
+		return result
+	}
+	local := now.In(loc)
+	stamp := local.Format("Mon 15:04 MST")
+
+	if len(schedule.Days) > 0 {
+		dayMatched := false
+		for _, day := range schedule.Days {
+			if weekdays[strings.ToLower(day)] == local.Weekday() {
+				dayMatched = true
+				break
+			}
+		}
+		if !dayMatched {
+			result.Reason = fmt.Sprintf("%s is not in days %s", stamp, strings.Join(schedule.Days, ","))
+			return result
+		}
+	}
+
+	if schedule.From != "" {
+		from, _ := parseClock(schedule.From)
+		to, _ := parseClock(schedule.To)
+		minute := local.Hour()*60 + local.Minute()
+
+		var inWindow bool
+		if from <= to {
+			inWindow = minute >= from && minute < to
+		} else {
+			// Окно через полночь, например 22:00-06:00
+			inWindow = minute >= from || minute < to
+		}
+		if !inWindow {
+			result.Reason = fmt.Sprintf("%s is outside %s-%s", stamp, schedule.From, schedule.To)
+			return result
+		}
+	}
+
+	result.Matched = true
+	result.Reason = fmt.Sprintf("%s is within the schedule", stamp)
+	return result
+}
+
+// location загружает часовой пояс с кэшированием; пустое значение означает UTC
+func (e *RuleEvaluator) location(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	if loc, ok := e.locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	e.locations.Store(name, loc)
+	return loc, nil
+}
+
+// PreferredLanguage возвращает тег языка с наибольшим весом из Accept-Language в нижнем регистре
+func PreferredLanguage(header string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if value, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+	return tags[0].tag
+}
+
+// parseClock разбирает время "ЧЧ:ММ" в минуты от полуночи
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// countMatched считает сработавшие условия
+func countMatched(results []ConditionResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Matched {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// resolveTimeout ограничение времени на разрешение имени хоста
+const resolveTimeout = 2 * time.Second
+
+// ErrPrivateTarget возвращается когда URL указывает на внутреннюю сеть
+var ErrPrivateTarget = errors.New("target points to a private or local network")
+
+// Resolver разрешает имена хостов в IP-адреса. *net.Resolver удовлетворяет интерфейсу,
+// в тестах можно подставить фейковую реализацию.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// URLPolicy защищает от SSRF: запрещает ссылки на loopback, link-local,
+// частные сети RFC 1918 и IPv6 ULA
+type URLPolicy struct {
+	blockPrivate bool
+	resolver     Resolver
+}
+
+// NewURLPolicy создаёт политику. Если resolver не nil, имена хостов дополнительно
+// разрешаются, и ссылка отклоняется, если хотя бы один адрес внутренний.
+func NewURLPolicy(blockPrivate bool, resolver Resolver) *URLPolicy {
+	return &URLPolicy{
+		blockPrivate: blockPrivate,
+		resolver:     resolver,
+	}
+}
+
+// Check проверяет, что URL не указывает на внутреннюю сеть
+func (p *URLPolicy) Check(ctx context.Context, rawURL string) error {
+	if p == nil || !p.blockPrivate {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	return p.CheckHost(ctx, u.Hostname())
+}
+
+// CheckHost проверяет имя хоста или IP-адрес
+func (p *URLPolicy) CheckHost(ctx context.Context, host string) error {
+	if p == nil || !p.blockPrivate {
+		return nil
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrPrivateTarget, host)
+	}
+
+	if ip := parseIPLiteral(host); ip != nil {
+		if IsPrivateIP(ip) {
+			return fmt.Errorf("%w: %s", ErrPrivateTarget, ip)
+		}
+		return nil
+	}
+
+	if p.resolver == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+
+	addrs, err := p.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve host %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if IsPrivateIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrPrivateTarget, host, addr.IP)
+		}
+	}
+
+	return nil
+}
+
+// CheckIP проверяет адрес, с которым устанавливается соединение. В отличие от Check,
+// проверяется уже разрешённый адрес, поэтому подмена DNS между проверкой и запросом не помогает.
+func (p *URLPolicy) CheckIP(ip net.IP) error {
+	if p == nil || !p.blockPrivate {
+		return nil
+	}
+	if IsPrivateIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateTarget, ip)
+	}
+	return nil
+}
+
+// IsPrivateIP сообщает, относится ли адрес к loopback, link-local, частным или неуказанным адресам
+func IsPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified()
+}
+
+// parseIPLiteral разбирает IP-адрес, включая нестандартные записи IPv4,
+// которые понимают браузеры и inet_aton: 2130706433, 0x7f.1, 0177.0.0.1
+func parseIPLiteral(host string) net.IP {
+	if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+		return ip
+	}
+
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+
+	values := make([]uint64, len(parts))
+	for i, part := range parts {
+		value, ok := parseIPv4Part(part)
+		if !ok {
+			return nil
+		}
+		values[i] = value
+	}
+
+	// Последняя часть заполняет все оставшиеся байты адреса
+	var addr uint64
+	for i, value := range values[:len(values)-1] {
+		if value > 0xff {
+			return nil
+		}
+		addr |= value << (8 * (3 - uint(i)))
+	}
+	last := values[len(values)-1]
+	if last >= 1<<(8*(5-uint(len(values)))) {
+		return nil
+	}
+	addr |= last
+
+	return net.IPv4(byte(addr>>24), byte(addr>>16), byte(addr>>8), byte(addr))
+}
+
+// parseIPv4Part разбирает часть IPv4 в десятичной, восьмеричной или шестнадцатеричной записи
+func parseIPv4Part(part string) (uint64, bool) {
+	if part == "" {
+		return 0, false
+	}
+
+	base := 10
+	switch {
+	case strings.HasPrefix(part, "0x") || strings.HasPrefix(part, "0X"):
+		base = 16
+		part = part[2:]
+		if part == "" {
+			return 0, true
+		}
+	case len(part) > 1 && part[0] == '0':
+		base = 8
+		part = part[1:]
+	}
+
+	value, err := strconv.ParseUint(part, base, 32)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+// fakeResolver разрешает имена по таблице
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	addrs := make([]net.IPAddr, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func TestURLPolicyCheck(t *testing.T) {
+	policy := NewURLPolicy(true, nil)
+
+	tests := []struct {
+		name    string
+		url     string
+		private bool
+	}{
+		{"public host", "https://example.com/path", false},
+		{"public IPv4", "http://93.184.216.34/", false},
+		{"public IPv6", "http://[2606:2800:220:1:248:1893:25c8:1946]/", false},
+		{"localhost", "http://localhost:8080/", true},
+		{"localhost subdomain", "http://api.localhost/", true},
+		{"localhost trailing dot", "http://LOCALHOST./", true},
+		{"loopback", "http://127.0.0.1/", true},
+		{"loopback range", "http://127.10.20.30/", true},
+		{"RFC1918 10/8", "http://10.0.0.5/", true},
+		{"RFC1918 172.16/12", "http://172.20.1.1/", true},
+		{"RFC1918 192.168/16", "http://192.168.1.1/", true},
+		{"outside 172.16/12", "http://172.32.0.1/", false},
+		{"cloud metadata", "http://169.254.169.254/latest/meta-data/", true},
+		{"unspecified", "http://0.0.0.0/", true},
+		{"IPv6 loopback", "http://[::1]/", true},
+		{"IPv6 ULA", "http://[fd00::1]/", true},
+		{"IPv6 link-local", "http://[fe80::1]/", true},
+		{"IPv4-mapped IPv6 loopback", "http://[::ffff:127.0.0.1]/", true},
+		{"shorthand 127.1", "http://127.1/", true},
+		{"hex shorthand", "http://0x7f.1/", true},
+		{"decimal", "http://2130706433/", true},
+		{"octal", "http://0177.0.0.1/", true},
+		{"hex RFC1918", "http://0xa.0.0.1/", true},
+		{"decimal public", "http://1572395042/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(context.Background(), tt.url)
+			if tt.private && !errors.Is(err, ErrPrivateTarget) {
+				t.Fatalf("Check(%q) = %v, want ErrPrivateTarget", tt.url, err)
+			}
+			if !tt.private && err != nil {
+				t.Fatalf("Check(%q) = %v, want nil", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestURLPolicyResolver(t *testing.T) {
+	resolver := fakeResolver{
+		"public.example":   {"93.184.216.34"},
+		"internal.example": {"10.1.2.3"},
+		"mixed.example":    {"93.184.216.34", "127.0.0.1"},
+		"metadata.example": {"169.254.169.254"},
+		"ula.example":      {"fd12:3456::1"},
+	}
+	policy := NewURLPolicy(true, resolver)
+
+	tests := []struct {
+		host    string
+		private bool
+	}{
+		{"public.example", false},
+		{"internal.example", true},
+		{"mixed.example", true},
+		{"metadata.example", true},
+		{"ula.example", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := policy.CheckHost(context.Background(), tt.host)
+			if tt.private && !errors.Is(err, ErrPrivateTarget) {
+				t.Fatalf("CheckHost(%q) = %v, want ErrPrivateTarget", tt.host, err)
+			}
+			if !tt.private && err != nil {
+				t.Fatalf("CheckHost(%q) = %v, want nil", tt.host, err)
+			}
+		})
+	}
+
+	t.Run("resolve error", func(t *testing.T) {
+		err := policy.CheckHost(context.Background(), "missing.example")
+		if err == nil || errors.Is(err, ErrPrivateTarget) {
+			t.Fatalf("CheckHost(missing.example) = %v, want resolve error", err)
+		}
+	})
+}
+
+func TestURLPolicyDisabled(t *testing.T) {
+	var nilPolicy *URLPolicy
+	if err := nilPolicy.Check(context.Background(), "http://127.0.0.1/"); err != nil {
+		t.Fatalf("nil policy Check = %v, want nil", err)
+	}
+
+	policy := NewURLPolicy(false, fakeResolver{})
+	if err := policy.Check(context.Background(), "http://10.0.0.1/"); err != nil {
+		t.Fatalf("disabled policy Check = %v, want nil", err)
+	}
+}
+
+func TestParseIPLiteral(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"127.1", "127.0.0.1"},
+		{"127.0.1", "127.0.0.1"},
+		{"0x7f.1", "127.0.0.1"},
+		{"0x7f000001", "127.0.0.1"},
+		{"2130706433", "127.0.0.1"},
+		{"0177.0.0.1", "127.0.0.1"},
+		{"10.0x10.1", "10.16.0.1"},
+		{"[::1]", "::1"},
+		{"example.com", ""},
+		{"256.1.1.1", ""},
+		{"1.2.3.4.5", ""},
+		{"4294967296", ""},
+		{"08.0.0.1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			ip := parseIPLiteral(tt.host)
+			if tt.want == "" {
+				if ip != nil {
+					t.Fatalf("parseIPLiteral(%q) = %s, want nil", tt.host, ip)
+				}
+				return
+			}
+			if !ip.Equal(net.ParseIP(tt.want)) {
+				t.Fatalf("parseIPLiteral(%q) = %s, want %s", tt.host, ip, tt.want)
+			}
+		})
+	}
+}
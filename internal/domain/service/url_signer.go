@@ -0,0 +1,105 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSignatureInvalid возвращается при неверной или неизвестной подписи
+	ErrSignatureInvalid = errors.New("invalid signature")
+	// ErrSignatureExpired возвращается когда срок действия подписанной ссылки истёк
+	ErrSignatureExpired = errors.New("signature expired")
+)
+
+// URLSigner подписывает короткие ссылки HMAC-SHA256 от кода и времени истечения.
+// Подпись имеет вид "<kid>.<hmac>": по идентификатору ключа проверка находит нужный секрет,
+// поэтому при ротации старые ключи остаются в списке до истечения выданных ссылок.
+type URLSigner struct {
+	keys      map[string][]byte
+	activeKey string
+}
+
+// NewURLSigner создаёт подписчика. keys — секреты по идентификаторам,
+// activeKey — идентификатор ключа, которым подписываются новые ссылки.
+func NewURLSigner(keys map[string][]byte, activeKey string) (*URLSigner, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one signing key is required")
+	}
+	for kid, secret := range keys {
+		if kid == "" || strings.Contains(kid, ".") {
+			return nil, fmt.Errorf("invalid signing key id %q", kid)
+		}
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("signing key %q is empty", kid)
+		}
+	}
+	if _, ok := keys[activeKey]; !ok {
+		return nil, fmt.Errorf("active signing key %q is not configured", activeKey)
+	}
+
+	return &URLSigner{
+		keys:      keys,
+		activeKey: activeKey,
+	}, nil
+}
+
+// ActiveKey возвращает идентификатор ключа, которым подписываются новые ссылки
+func (s *URLSigner) ActiveKey() string {
+	return s.activeKey
+}
+
+// Sign возвращает подпись для кода ссылки и времени истечения
+func (s *URLSigner) Sign(shortCode string, expires time.Time) string {
+	return s.activeKey + "." + s.mac(s.keys[s.activeKey], shortCode, expires.Unix())
+}
+
+// SignURL добавляет к короткому URL параметры exp и sig
+func (s *URLSigner) SignURL(shortURL, shortCode string, expires time.Time) string {
+	query := url.Values{}
+	query.Set("exp", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("sig", s.Sign(shortCode, expires))
+	return shortURL + "?" + query.Encode()
+}
+
+// Verify проверяет подпись и срок действия. exp — значение параметра exp (Unix-время в секундах).
+func (s *URLSigner) Verify(shortCode, exp, signature string, now time.Time) error {
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+
+	kid, sig, ok := strings.Cut(signature, ".")
+	if !ok {
+		return ErrSignatureInvalid
+	}
+	secret, ok := s.keys[kid]
+	if !ok {
+		return ErrSignatureInvalid
+	}
+
+	// Сначала проверяем подпись, чтобы не раскрывать срок действия поддельных ссылок
+	expected := s.mac(secret, shortCode, expires)
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		return ErrSignatureInvalid
+	}
+
+	if now.Unix() > expires {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// mac вычисляет HMAC для кода ссылки и времени истечения
+func (s *URLSigner) mac(secret []byte, shortCode string, expires int64) string {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(shortCode + "\n" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
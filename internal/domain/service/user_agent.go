@@ -0,0 +1,167 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// Операционные системы
+const (
+	OSiOS      = "ios"
+	OSAndroid  = "android"
+	OSWindows  = "windows"
+	OSMacOS    = "macos"
+	OSLinux    = "linux"
+	OSChromeOS = "chromeos"
+	OSOther    = "other"
+)
+
+// Типы устройств
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+)
+
+// Браузеры
+const (
+	BrowserChrome  = "chrome"
+	BrowserSafari  = "safari"
+	BrowserFirefox = "firefox"
+	BrowserEdge    = "edge"
+	BrowserOpera   = "opera"
+	BrowserSamsung = "samsung"
+	BrowserYandex  = "yandex"
+	BrowserOther   = "other"
+)
+
+// KnownOS, KnownDeviceTypes и KnownBrowsers допустимые значения в правилах таргетинга
+var (
+	KnownOS          = []string{OSiOS, OSAndroid, OSWindows, OSMacOS, OSLinux, OSChromeOS, OSOther}
+	KnownDeviceTypes = []string{DeviceMobile, DeviceTablet, DeviceDesktop, DeviceBot}
+	KnownBrowsers    = []string{BrowserChrome, BrowserSafari, BrowserFirefox, BrowserEdge, BrowserOpera,
+		BrowserSamsung, BrowserYandex, BrowserOther}
+)
+
+// botMarkers подстроки User-Agent, по которым определяются боты и консольные клиенты
+var botMarkers = []string{"bot", "crawler", "spider", "slurp", "facebookexternalhit", "curl/", "wget/",
+	"python-requests", "go-http-client"}
+
+// previewBotMarkers подстроки User-Agent ботов, которые строят превью ссылок в соцсетях
+// и мессенджерах. Поисковые роботы сюда не входят: им нужен обычный редирект.
+var previewBotMarkers = []string{"facebookexternalhit", "facebookcatalog", "facebot", "twitterbot",
+	"linkedinbot", "slackbot", "slack-imgproxy", "discordbot", "telegrambot", "whatsapp", "skypeuripreview",
+	"vkshare", "pinterestbot", "redditbot", "embedly", "mastodon", "iframely", "viber", "snapchat",
+	"google-pagerenderer", "tumblr", "bitlybot"}
+
+// IsPreviewBot проверяет, что запрос отправил бот, строящий превью ссылки
+func IsPreviewBot(header string) bool {
+	return containsAny(strings.ToLower(header), previewBotMarkers...)
+}
+
+// UserAgent атрибуты клиента, извлечённые из заголовка User-Agent
+type UserAgent struct {
+	OS         string
+	DeviceType string
+	Browser    string
+}
+
+// ParseUserAgent разбирает заголовок User-Agent. Разбор эвристический и рассчитан
+// на распространённые браузеры; неизвестные значения получают "other" и "desktop".
+func ParseUserAgent(header string) UserAgent {
+	ua := strings.ToLower(header)
+	result := UserAgent{
+		OS:         parseOS(ua),
+		DeviceType: DeviceDesktop,
+		Browser:    parseBrowser(ua),
+	}
+
+	switch {
+	case containsAny(ua, botMarkers...):
+		result.DeviceType = DeviceBot
+	case strings.Contains(ua, "ipad"), strings.Contains(ua, "tablet"),
+		result.OS == OSAndroid && !strings.Contains(ua, "mobile"):
+		result.DeviceType = DeviceTablet
+	case containsAny(ua, "iphone", "ipod", "mobile", "windows phone"):
+		result.DeviceType = DeviceMobile
+	}
+
+	return result
+}
+
+// parseOS определяет операционную систему; порядок проверок важен,
+// так как User-Agent мобильных систем содержит названия настольных
+func parseOS(ua string) string {
+	switch {
+	case containsAny(ua, "iphone", "ipad", "ipod"):
+		return OSiOS
+	case strings.Contains(ua, "android"):
+		return OSAndroid
+	case strings.Contains(ua, "windows"):
+		return OSWindows
+	case strings.Contains(ua, "cros "):
+		return OSChromeOS
+	case containsAny(ua, "macintosh", "mac os x"):
+		return OSMacOS
+	case strings.Contains(ua, "linux"):
+		return OSLinux
+	default:
+		return OSOther
+	}
+}
+
+// parseBrowser определяет браузер; большинство браузеров добавляют в User-Agent
+// маркеры Chrome и Safari, поэтому сначала проверяются более специфичные
+func parseBrowser(ua string) string {
+	switch {
+	case containsAny(ua, "edg/", "edge/", "edga/", "edgios/"):
+		return BrowserEdge
+	case containsAny(ua, "opr/", "opera"):
+		return BrowserOpera
+	case strings.Contains(ua, "samsungbrowser"):
+		return BrowserSamsung
+	case strings.Contains(ua, "yabrowser"):
+		return BrowserYandex
+	case containsAny(ua, "firefox/", "fxios/"):
+		return BrowserFirefox
+	case containsAny(ua, "chrome/", "crios/", "chromium/"):
+		return BrowserChrome
+	case strings.Contains(ua, "safari/"):
+		return BrowserSafari
+	default:
+		return BrowserOther
+	}
+}
+
+// MatchTargetingRule проверяет, подходит ли клиент под правило. Внутри списка значения
+// объединяются через ИЛИ, пустой список не ограничивает атрибут.
+func MatchTargetingRule(rule entity.TargetingRule, ua UserAgent) bool {
+	return matchValue(rule.OS, ua.OS) &&
+		matchValue(rule.DeviceType, ua.DeviceType) &&
+		matchValue(rule.Browser, ua.Browser)
+}
+
+// matchValue проверяет вхождение значения в список
+func matchValue(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, v := range allowed {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAny проверяет, содержит ли строка хотя бы одну из подстрок
+func containsAny(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
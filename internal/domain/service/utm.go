@@ -0,0 +1,96 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// utmKeys имена UTM-параметров в порядке, в котором они добавляются в URL
+var utmKeys = []string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content"}
+
+// utmValue возвращает значение метки по имени параметра
+func utmValue(params entity.UTMParams, key string) string {
+	switch key {
+	case "utm_source":
+		return params.Source
+	case "utm_medium":
+		return params.Medium
+	case "utm_campaign":
+		return params.Campaign
+	case "utm_term":
+		return params.Term
+	case "utm_content":
+		return params.Content
+	}
+	return ""
+}
+
+// ParseUTM извлекает UTM-метки из query адреса. Имена параметров сравниваются
+// без учёта регистра; для повторяющегося параметра берётся первое значение.
+func ParseUTM(rawURL string) entity.UTMParams {
+	var params entity.UTMParams
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return params
+	}
+
+	for key, values := range u.Query() {
+		value := strings.TrimSpace(values[0])
+		switch strings.ToLower(key) {
+		case "utm_source":
+			params.Source = value
+		case "utm_medium":
+			params.Medium = value
+		case "utm_campaign":
+			params.Campaign = value
+		case "utm_term":
+			params.Term = value
+		case "utm_content":
+			params.Content = value
+		}
+	}
+
+	return params
+}
+
+// ApplyUTM добавляет UTM-метки в адрес. defaults заполняют только отсутствующие в адресе
+// метки, overrides заменяют любые. Остальные параметры и их порядок не меняются.
+func ApplyUTM(rawURL string, defaults, overrides entity.UTMParams) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	current := ParseUTM(rawURL)
+	target := defaults.Merge(current).Merge(overrides)
+	if target == current {
+		return rawURL, nil
+	}
+
+	// Изменяемые метки удаляются из исходного query и добавляются в конец
+	var parts []string
+	for _, part := range strings.Split(u.RawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(part, "=")
+		if name, err := url.QueryUnescape(name); err == nil {
+			key := strings.ToLower(name)
+			if value := utmValue(target, key); value != "" && value != utmValue(current, key) {
+				continue
+			}
+		}
+		parts = append(parts, part)
+	}
+	for _, key := range utmKeys {
+		if value := utmValue(target, key); value != "" && value != utmValue(current, key) {
+			parts = append(parts, key+"="+url.QueryEscape(value))
+		}
+	}
+
+	u.RawQuery = strings.Join(parts, "&")
+	return u.String(), nil
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval как часто удаляются просроченные записи
+const memorySweepInterval = time.Minute
+
+// memoryEntry запись in-memory кэша
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// MemoryCache кэш в памяти процесса, используется когда Redis не подключён.
+// Значения сериализуются в JSON, чтобы поведение совпадало с RedisCache.
+type MemoryCache struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	ttl       time.Duration
+	lastSweep time.Time
+}
+
+// NewMemoryCache создаёт новый in-memory кэш
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		entries:   make(map[string]memoryEntry),
+		ttl:       ttl,
+		lastSweep: time.Now(),
+	}
+}
+
+// Get получает значение из кэша
+func (m *MemoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(m.entries, key)
+		ok = false
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return ErrCacheMiss
+	}
+
+	if err := json.Unmarshal(entry.data, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal cache value: %w", err)
+	}
+
+	return nil
+}
+
+// Set сохраняет значение в кэш
+func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}) error {
+	return m.SetWithTTL(ctx, key, value, m.ttl)
+}
+
+// SetWithTTL сохраняет значение в кэш с явным временем жизни
+func (m *MemoryCache) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache value: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweepLocked(now)
+	m.entries[key] = memoryEntry{data: data, expiresAt: now.Add(ttl)}
+
+	return nil
+}
+
+// SetIfNotExists сохраняет значение, только если ключ отсутствует
+func (m *MemoryCache) SetIfNotExists(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal cache value: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweepLocked(now)
+	if entry, ok := m.entries[key]; ok && now.Before(entry.expiresAt) {
+		return false, nil
+	}
+	m.entries[key] = memoryEntry{data: data, expiresAt: now.Add(ttl)}
+
+	return true, nil
+}
+
+// Delete удаляет значение из кэша
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.entries, key)
+	m.mu.Unlock()
+	return nil
+}
+
+// sweepLocked периодически удаляет просроченные записи, вызывается под блокировкой
+func (m *MemoryCache) sweepLocked(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	for key, entry := range m.entries {
+		if now.After(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	m.lastSweep = now
+}
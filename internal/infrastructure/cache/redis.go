@@ -65,6 +65,35 @@ func (r *RedisCache) Set(ctx context.Context, key string, value interface{}) err
 	return nil
 }
 
+// SetWithTTL сохраняет значение в кэш с явным временем жизни
+func (r *RedisCache) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache value: %w", err)
+	}
+
+	if err := r.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set cache: %w", err)
+	}
+
+	return nil
+}
+
+// SetIfNotExists сохраняет значение, только если ключ отсутствует (SET NX)
+func (r *RedisCache) SetIfNotExists(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal cache value: %w", err)
+	}
+
+	ok, err := r.client.SetNX(ctx, key, data, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to set cache: %w", err)
+	}
+
+	return ok, nil
+}
+
 // Delete удаляет значение из кэша
 func (r *RedisCache) Delete(ctx context.Context, key string) error {
 	if err := r.client.Del(ctx, key).Err(); err != nil {
@@ -73,6 +102,11 @@ func (r *RedisCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Client возвращает клиент Redis для компонентов, которым нужны команды помимо кэша
+func (r *RedisCache) Client() *redis.Client {
+	return r.client
+}
+
 // Close закрывает подключение к Redis
 func (r *RedisCache) Close() error {
 	return r.client.Close()
@@ -0,0 +1,239 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// CampaignRepositoryImpl реализует repository.CampaignRepository
+type CampaignRepositoryImpl struct {
+	db *PostgresDB
+}
+
+// NewCampaignRepository создаёт новый репозиторий кампаний
+func NewCampaignRepository(db *PostgresDB) repository.CampaignRepository {
+	return &CampaignRepositoryImpl{db: db}
+}
+
+// campaignColumns список колонок кампании с числом ссылок в порядке, ожидаемом scanCampaign
+const campaignColumns = `c.id, c.name, c.description, c.created_at,
+	(SELECT COUNT(*) FROM links l WHERE l.campaign = c.name)`
+
+// scanCampaign считывает кампанию из строки результата запроса
+func scanCampaign(row rowScanner) (*entity.Campaign, error) {
+	campaign := &entity.Campaign{}
+	if err := row.Scan(
+		&campaign.ID,
+		&campaign.Name,
+		&campaign.Description,
+		&campaign.CreatedAt,
+		&campaign.LinkCount,
+	); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func (r *CampaignRepositoryImpl) Create(ctx context.Context, campaign *entity.Campaign) error {
+	query := `INSERT INTO campaigns (name, description, created_at)
+			  VALUES ($1, $2, $3) RETURNING id`
+
+	err := r.db.db.QueryRowContext(ctx, query,
+		campaign.Name,
+		campaign.Description,
+		campaign.CreatedAt,
+	).Scan(&campaign.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CampaignRepositoryImpl) GetByName(ctx context.Context, name string) (*entity.Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns c WHERE c.name = $1`
+
+	campaign, err := scanCampaign(r.db.db.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+func (r *CampaignRepositoryImpl) List(ctx context.Context) ([]*entity.Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns c ORDER BY c.name`
+
+	rows, err := r.db.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	var campaigns []*entity.Campaign
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan campaign: %w", err)
+		}
+		campaigns = append(campaigns, campaign)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+func (r *CampaignRepositoryImpl) Update(ctx context.Context, campaign *entity.Campaign) (bool, error) {
+	result, err := r.db.db.ExecContext(ctx, `UPDATE campaigns SET description = $2 WHERE name = $1`,
+		campaign.Name, campaign.Description)
+	if err != nil {
+		return false, fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *CampaignRepositoryImpl) Delete(ctx context.Context, name string) (bool, error) {
+	// Ссылки остаются без кампании благодаря ON DELETE SET NULL
+	result, err := r.db.db.ExecContext(ctx, `DELETE FROM campaigns WHERE name = $1`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete campaign: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete campaign: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *CampaignRepositoryImpl) GetAnalytics(ctx context.Context, name string) (*entity.CampaignAnalytics, error) {
+	analytics := &entity.CampaignAnalytics{
+		Campaign: name,
+		ByDay:    make(map[string]int64),
+		ByLink:   make(map[string]int64),
+	}
+
+	// Итоги считаются в БД, ссылки кампании не загружаются в память
+	totalsQuery := `SELECT
+				(SELECT COUNT(*) FROM links WHERE campaign = $1),
+				(SELECT COUNT(*) FROM clicks c JOIN links l ON l.id = c.link_id WHERE l.campaign = $1),
+				(SELECT COUNT(*) FROM conversions cv JOIN links l ON l.id = cv.link_id WHERE l.campaign = $1)`
+	err := r.db.db.QueryRowContext(ctx, totalsQuery, name).Scan(
+		&analytics.Links,
+		&analytics.TotalClicks,
+		&analytics.TotalConversions,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign totals: %w", err)
+	}
+
+	// Группировка по дням
+	dayQuery := `SELECT DATE(c.clicked_at) as day, COUNT(*) as count
+				 FROM clicks c JOIN links l ON l.id = c.link_id
+				 WHERE l.campaign = $1
+				 GROUP BY DATE(c.clicked_at) ORDER BY day DESC`
+	rows, err := r.db.db.QueryContext(ctx, dayQuery, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign clicks by day: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day time.Time
+		var count int64
+		if err := rows.Scan(&day, &count); err != nil {
+			continue
+		}
+		analytics.ByDay[day.Format("2006-01-02")] = count
+	}
+
+	// Переходы по ссылкам, включая ссылки без переходов
+	linkQuery := `SELECT l.short_url, COUNT(c.id) as count
+				  FROM links l LEFT JOIN clicks c ON c.link_id = l.id
+				  WHERE l.campaign = $1
+				  GROUP BY l.short_url`
+	rows, err = r.db.db.QueryContext(ctx, linkQuery, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign clicks by link: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var shortURL string
+		var count int64
+		if err := rows.Scan(&shortURL, &count); err != nil {
+			continue
+		}
+		analytics.ByLink[shortURL] = count
+	}
+
+	return analytics, nil
+}
+
+// TagRepositoryImpl реализует repository.TagRepository
+type TagRepositoryImpl struct {
+	db *PostgresDB
+}
+
+// NewTagRepository создаёт новый репозиторий тегов
+func NewTagRepository(db *PostgresDB) repository.TagRepository {
+	return &TagRepositoryImpl{db: db}
+}
+
+func (r *TagRepositoryImpl) List(ctx context.Context) ([]*entity.Tag, error) {
+	query := `SELECT t.name, COUNT(lt.link_id)
+			  FROM tags t LEFT JOIN link_tags lt ON lt.tag_id = t.id
+			  GROUP BY t.name ORDER BY t.name`
+
+	rows, err := r.db.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []*entity.Tag
+	for rows.Next() {
+		tag := &entity.Tag{}
+		if err := rows.Scan(&tag.Name, &tag.LinkCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepositoryImpl) Delete(ctx context.Context, name string) (bool, error) {
+	// Связи со ссылками удаляются каскадно
+	result, err := r.db.db.ExecContext(ctx, `DELETE FROM tags WHERE name = $1`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// ConversionRepositoryImpl реализует repository.ConversionRepository
+type ConversionRepositoryImpl struct {
+	db *PostgresDB
+}
+
+// NewConversionRepository создаёт новый репозиторий конверсий
+func NewConversionRepository(db *PostgresDB) repository.ConversionRepository {
+	return &ConversionRepositoryImpl{db: db}
+}
+
+func (r *ConversionRepositoryImpl) Create(ctx context.Context, conversion *entity.Conversion) error {
+	query := `INSERT INTO conversions (link_id, variant, created_at)
+			  VALUES ($1, $2, $3) RETURNING id`
+
+	err := r.db.db.QueryRowContext(ctx, query,
+		conversion.LinkID,
+		conversion.Variant,
+		conversion.CreatedAt,
+	).Scan(&conversion.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create conversion: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ConversionRepositoryImpl) CountByVariant(ctx context.Context, linkID int64) (map[string]int64, error) {
+	query := `SELECT variant, COUNT(*) FROM conversions WHERE link_id = $1 GROUP BY variant`
+
+	rows, err := r.db.db.QueryContext(ctx, query, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count conversions: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var variant string
+		var count int64
+		if err := rows.Scan(&variant, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan conversions: %w", err)
+		}
+		counts[variant] = count
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// DomainRuleRepositoryImpl реализует repository.DomainRuleRepository
+type DomainRuleRepositoryImpl struct {
+	db *PostgresDB
+}
+
+// NewDomainRuleRepository создаёт новый репозиторий правил доменов
+func NewDomainRuleRepository(db *PostgresDB) repository.DomainRuleRepository {
+	return &DomainRuleRepositoryImpl{db: db}
+}
+
+func (r *DomainRuleRepositoryImpl) Create(ctx context.Context, rule *entity.DomainRule) error {
+	query := `INSERT INTO domain_rules (action, kind, pattern, comment, created_at)
+			  VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	err := r.db.db.QueryRowContext(ctx, query,
+		rule.Action,
+		rule.Kind,
+		rule.Pattern,
+		rule.Comment,
+		rule.CreatedAt,
+	).Scan(&rule.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create domain rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DomainRuleRepositoryImpl) Delete(ctx context.Context, id int64) (bool, error) {
+	result, err := r.db.db.ExecContext(ctx, `DELETE FROM domain_rules WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete domain rule: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete domain rule: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *DomainRuleRepositoryImpl) List(ctx context.Context) ([]*entity.DomainRule, error) {
+	query := `SELECT id, action, kind, pattern, comment, created_at
+			  FROM domain_rules ORDER BY id`
+
+	rows, err := r.db.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domain rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*entity.DomainRule
+	for rows.Next() {
+		rule := &entity.DomainRule{}
+		if err := rows.Scan(
+			&rule.ID,
+			&rule.Action,
+			&rule.Kind,
+			&rule.Pattern,
+			&rule.Comment,
+			&rule.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan domain rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list domain rules: %w", err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,232 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// HealthRepositoryImpl реализует repository.HealthRepository
+type HealthRepositoryImpl struct {
+	db *PostgresDB
+}
+
+// NewHealthRepository создаёт новый репозиторий проверок доступности
+func NewHealthRepository(db *PostgresDB) repository.HealthRepository {
+	return &HealthRepositoryImpl{db: db}
+}
+
+func (r *HealthRepositoryImpl) ListDue(ctx context.Context, before time.Time, limit int) ([]*entity.Link, error) {
+	// Истёкшие ссылки не проверяются: переходы по ним уже невозможны
+	query := `SELECT ` + linkColumns + ` FROM links
+			  WHERE (active_until IS NULL OR active_until > $1)
+			    AND (health IS NULL OR (health->>'checked_at')::timestamptz < $2)
+			  ORDER BY (health->>'checked_at')::timestamptz NULLS FIRST, id
+			  LIMIT $3`
+
+	rows, err := r.db.db.QueryContext(ctx, query, time.Now().UTC(), before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links due for health check: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*entity.Link
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan link: %w", err)
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list links due for health check: %w", err)
+	}
+
+	return links, nil
+}
+
+func (r *HealthRepositoryImpl) SaveCheck(ctx context.Context, check *entity.HealthCheck, health *entity.LinkHealth, keep int) error {
+	redirects, err := marshalJSONColumn(check.Redirects, len(check.Redirects) == 0)
+	if err != nil {
+		return fmt.Errorf("failed to encode redirects: %w", err)
+	}
+	state, err := json.Marshal(health)
+	if err != nil {
+		return fmt.Errorf("failed to encode link health: %w", err)
+	}
+
+	tx, err := r.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO health_checks (link_id, healthy, status_code, method, redirects, final_url, error, duration_ms, checked_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	err = tx.QueryRowContext(ctx, query,
+		check.LinkID,
+		check.Healthy,
+		check.StatusCode,
+		check.Method,
+		redirects,
+		check.FinalURL,
+		check.Error,
+		check.DurationMs,
+		check.CheckedAt,
+	).Scan(&check.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create health check: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE links SET health = $2 WHERE id = $1`, check.LinkID, string(state)); err != nil {
+		return fmt.Errorf("failed to update link health: %w", err)
+	}
+
+	// История ограничена, чтобы частые проверки не раздували таблицу
+	pruneQuery := `DELETE FROM health_checks WHERE link_id = $1 AND id NOT IN (
+				   SELECT id FROM health_checks WHERE link_id = $1 ORDER BY checked_at DESC, id DESC LIMIT $2)`
+	if _, err := tx.ExecContext(ctx, pruneQuery, check.LinkID, keep); err != nil {
+		return fmt.Errorf("failed to prune health checks: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit health check: %w", err)
+	}
+	return nil
+}
+
+func (r *HealthRepositoryImpl) ListChecks(ctx context.Context, linkID int64, limit int) ([]*entity.HealthCheck, error) {
+	query := `SELECT id, link_id, healthy, status_code, method, redirects, final_url, error, duration_ms, checked_at
+			  FROM health_checks WHERE link_id = $1
+			  ORDER BY checked_at DESC, id DESC LIMIT $2`
+
+	rows, err := r.db.db.QueryContext(ctx, query, linkID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list health checks: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []*entity.HealthCheck
+	for rows.Next() {
+		check := &entity.HealthCheck{}
+		var redirects []byte
+		if err := rows.Scan(
+			&check.ID,
+			&check.LinkID,
+			&check.Healthy,
+			&check.StatusCode,
+			&check.Method,
+			&redirects,
+			&check.FinalURL,
+			&check.Error,
+			&check.DurationMs,
+			&check.CheckedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan health check: %w", err)
+		}
+		if err := unmarshalJSONColumn(redirects, &check.Redirects); err != nil {
+			return nil, fmt.Errorf("failed to decode redirects: %w", err)
+		}
+		checks = append(checks, check)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list health checks: %w", err)
+	}
+
+	return checks, nil
+}
+
+// HealthSubscriptionRepositoryImpl реализует repository.HealthSubscriptionRepository
+type HealthSubscriptionRepositoryImpl struct {
+	db *PostgresDB
+}
+
+// NewHealthSubscriptionRepository создаёт новый репозиторий подписок на уведомления
+func NewHealthSubscriptionRepository(db *PostgresDB) repository.HealthSubscriptionRepository {
+	return &HealthSubscriptionRepositoryImpl{db: db}
+}
+
+// subscriptionColumns список колонок таблицы health_subscriptions в порядке, ожидаемом scanSubscription
+const subscriptionColumns = `id, owner, webhook_url, secret, created_at`
+
+// scanSubscription считывает подписку из строки результата запроса
+func scanSubscription(row rowScanner) (*entity.HealthSubscription, error) {
+	subscription := &entity.HealthSubscription{}
+	if err := row.Scan(
+		&subscription.ID,
+		&subscription.Owner,
+		&subscription.WebhookURL,
+		&subscription.Secret,
+		&subscription.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *HealthSubscriptionRepositoryImpl) Create(ctx context.Context, subscription *entity.HealthSubscription) error {
+	query := `INSERT INTO health_subscriptions (owner, webhook_url, secret, created_at)
+			  VALUES ($1, $2, $3, $4) RETURNING id`
+
+	err := r.db.db.QueryRowContext(ctx, query,
+		subscription.Owner,
+		subscription.WebhookURL,
+		subscription.Secret,
+		subscription.CreatedAt,
+	).Scan(&subscription.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create health subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (r *HealthSubscriptionRepositoryImpl) List(ctx context.Context) ([]*entity.HealthSubscription, error) {
+	return r.query(ctx, `SELECT `+subscriptionColumns+` FROM health_subscriptions ORDER BY id`)
+}
+
+func (r *HealthSubscriptionRepositoryImpl) ListForOwner(ctx context.Context, owner string) ([]*entity.HealthSubscription, error) {
+	return r.query(ctx, `SELECT `+subscriptionColumns+` FROM health_subscriptions
+			  WHERE owner = $1 OR owner = '' ORDER BY id`, owner)
+}
+
+func (r *HealthSubscriptionRepositoryImpl) Delete(ctx context.Context, id int64) (bool, error) {
+	result, err := r.db.db.ExecContext(ctx, `DELETE FROM health_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete health subscription: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete health subscription: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// query выполняет запрос и считывает подписки
+func (r *HealthSubscriptionRepositoryImpl) query(ctx context.Context, query string, args ...interface{}) ([]*entity.HealthSubscription, error) {
+	rows, err := r.db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list health subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*entity.HealthSubscription
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan health subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list health subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
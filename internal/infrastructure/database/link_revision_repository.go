@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// LinkRevisionRepositoryImpl реализует repository.LinkRevisionRepository
+type LinkRevisionRepositoryImpl struct {
+	db *PostgresDB
+}
+
+// NewLinkRevisionRepository создаёт новый репозиторий истории ссылок
+func NewLinkRevisionRepository(db *PostgresDB) repository.LinkRevisionRepository {
+	return &LinkRevisionRepositoryImpl{db: db}
+}
+
+// revisionColumns список колонок таблицы link_revisions в порядке, ожидаемом scanRevision
+const revisionColumns = `id, link_id, revision, action, COALESCE(restored_from, 0), state, created_at`
+
+// insertRevision записывает текущее состояние ссылки как ревизию link.Revision
+func insertRevision(ctx context.Context, tx *sql.Tx, link *entity.Link, revision *entity.LinkRevision) error {
+	query := `INSERT INTO link_revisions (link_id, revision, action, restored_from, state, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+
+	revision.LinkID = link.ID
+	revision.Revision = link.Revision
+	revision.State = link.State()
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = link.CreatedAt
+	}
+
+	state, err := json.Marshal(revision.State)
+	if err != nil {
+		return fmt.Errorf("failed to encode revision state: %w", err)
+	}
+	var restoredFrom interface{}
+	if revision.RestoredFrom != 0 {
+		restoredFrom = revision.RestoredFrom
+	}
+
+	err = tx.QueryRowContext(ctx, query,
+		revision.LinkID,
+		revision.Revision,
+		revision.Action,
+		restoredFrom,
+		string(state),
+		revision.CreatedAt,
+	).Scan(&revision.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create link revision: %w", err)
+	}
+
+	return nil
+}
+
+// scanRevision считывает ревизию из строки результата запроса
+func scanRevision(row rowScanner) (*entity.LinkRevision, error) {
+	revision := &entity.LinkRevision{}
+	var state []byte
+	if err := row.Scan(
+		&revision.ID,
+		&revision.LinkID,
+		&revision.Revision,
+		&revision.Action,
+		&revision.RestoredFrom,
+		&state,
+		&revision.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalJSONColumn(state, &revision.State); err != nil {
+		return nil, fmt.Errorf("failed to decode revision state: %w", err)
+	}
+
+	return revision, nil
+}
+
+func (r *LinkRevisionRepositoryImpl) ListByLinkID(ctx context.Context, linkID int64) ([]*entity.LinkRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM link_revisions WHERE link_id = $1 ORDER BY revision DESC`
+
+	rows, err := r.db.db.QueryContext(ctx, query, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list link revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []*entity.LinkRevision
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan link revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list link revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+func (r *LinkRevisionRepositoryImpl) GetByRevision(ctx context.Context, linkID int64, revision int) (*entity.LinkRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM link_revisions WHERE link_id = $1 AND revision = $2`
+
+	result, err := scanRevision(r.db.db.QueryRowContext(ctx, query, linkID, revision))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link revision: %w", err)
+	}
+
+	return result, nil
+}
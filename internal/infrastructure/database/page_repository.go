@@ -0,0 +1,289 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// PageRepositoryImpl реализует repository.PageRepository
+type PageRepositoryImpl struct {
+	db *PostgresDB
+}
+
+// NewPageRepository создаёт новый репозиторий страниц ссылок
+func NewPageRepository(db *PostgresDB) repository.PageRepository {
+	return &PageRepositoryImpl{db: db}
+}
+
+// pageColumns список колонок таблицы pages в порядке, ожидаемом scanPage
+const pageColumns = `id, slug, title, description, created_at, updated_at`
+
+// scanPage считывает страницу без пунктов из строки результата запроса
+func scanPage(row rowScanner) (*entity.Page, error) {
+	page := &entity.Page{}
+	if err := row.Scan(
+		&page.ID,
+		&page.Slug,
+		&page.Title,
+		&page.Description,
+		&page.CreatedAt,
+		&page.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (r *PageRepositoryImpl) Create(ctx context.Context, page *entity.Page) error {
+	query := `INSERT INTO pages (slug, title, description, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	tx, err := r.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, query,
+		page.Slug,
+		page.Title,
+		page.Description,
+		page.CreatedAt,
+		page.UpdatedAt,
+	).Scan(&page.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create page: %w", err)
+	}
+
+	if err := insertPageItems(ctx, tx, page); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit page: %w", err)
+	}
+	return nil
+}
+
+func (r *PageRepositoryImpl) GetBySlug(ctx context.Context, slug string) (*entity.Page, error) {
+	query := `SELECT ` + pageColumns + ` FROM pages WHERE slug = $1`
+
+	page, err := scanPage(r.db.db.QueryRowContext(ctx, query, slug))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page: %w", err)
+	}
+
+	if page.Items, err = r.listItems(ctx, page.ID); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (r *PageRepositoryImpl) List(ctx context.Context) ([]*entity.Page, error) {
+	query := `SELECT ` + pageColumns + ` FROM pages ORDER BY slug`
+
+	rows, err := r.db.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pages: %w", err)
+	}
+	defer rows.Close()
+
+	var pages []*entity.Page
+	for rows.Next() {
+		page, err := scanPage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan page: %w", err)
+		}
+		pages = append(pages, page)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list pages: %w", err)
+	}
+
+	for _, page := range pages {
+		if page.Items, err = r.listItems(ctx, page.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return pages, nil
+}
+
+func (r *PageRepositoryImpl) Update(ctx context.Context, page *entity.Page) (bool, error) {
+	query := `UPDATE pages SET title = $2, description = $3, updated_at = $4
+			  WHERE slug = $1 RETURNING id, created_at`
+
+	tx, err := r.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, query,
+		page.Slug,
+		page.Title,
+		page.Description,
+		page.UpdatedAt,
+	).Scan(&page.ID, &page.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update page: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM page_items WHERE page_id = $1`, page.ID); err != nil {
+		return false, fmt.Errorf("failed to clear page items: %w", err)
+	}
+	if err := insertPageItems(ctx, tx, page); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit page: %w", err)
+	}
+	return true, nil
+}
+
+func (r *PageRepositoryImpl) Delete(ctx context.Context, slug string) (bool, error) {
+	tx, err := r.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Просмотры без ссылки удаляются вместе со страницей, у переходов по пунктам
+	// page_id обнуляется внешним ключом
+	if _, err := tx.ExecContext(ctx, `DELETE FROM clicks
+			  WHERE link_id IS NULL AND page_id = (SELECT id FROM pages WHERE slug = $1)`, slug); err != nil {
+		return false, fmt.Errorf("failed to delete page views: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM pages WHERE slug = $1`, slug)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete page: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete page: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit page deletion: %w", err)
+	}
+	return affected > 0, nil
+}
+
+func (r *PageRepositoryImpl) GetAnalytics(ctx context.Context, pageID int64) (*entity.PageAnalytics, error) {
+	analytics := &entity.PageAnalytics{
+		ViewsByDay: make(map[string]int64),
+		ByItem:     make(map[string]int64),
+	}
+
+	totalsQuery := `SELECT p.slug,
+					(SELECT COUNT(*) FROM clicks WHERE page_id = p.id AND link_id IS NULL),
+					(SELECT COUNT(*) FROM clicks WHERE page_id = p.id AND link_id IS NOT NULL)
+					FROM pages p WHERE p.id = $1`
+	err := r.db.db.QueryRowContext(ctx, totalsQuery, pageID).Scan(
+		&analytics.Slug,
+		&analytics.Views,
+		&analytics.ItemClicks,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page totals: %w", err)
+	}
+
+	// Просмотры по дням
+	dayQuery := `SELECT DATE(clicked_at) as day, COUNT(*) as count
+				 FROM clicks WHERE page_id = $1 AND link_id IS NULL
+				 GROUP BY DATE(clicked_at) ORDER BY day DESC`
+	rows, err := r.db.db.QueryContext(ctx, dayQuery, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page views by day: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day time.Time
+		var count int64
+		if err := rows.Scan(&day, &count); err != nil {
+			continue
+		}
+		analytics.ViewsByDay[day.Format("2006-01-02")] = count
+	}
+
+	// Переходы по пунктам
+	itemQuery := `SELECT l.short_url, COUNT(*) as count
+				  FROM clicks c JOIN links l ON l.id = c.link_id
+				  WHERE c.page_id = $1
+				  GROUP BY l.short_url`
+	rows, err = r.db.db.QueryContext(ctx, itemQuery, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page clicks by item: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var shortURL string
+		var count int64
+		if err := rows.Scan(&shortURL, &count); err != nil {
+			continue
+		}
+		analytics.ByItem[shortURL] = count
+	}
+
+	return analytics, nil
+}
+
+// listItems возвращает пункты страницы по порядку
+func (r *PageRepositoryImpl) listItems(ctx context.Context, pageID int64) ([]entity.PageItem, error) {
+	query := `SELECT l.short_url, i.title, i.icon
+			  FROM page_items i JOIN links l ON l.id = i.link_id
+			  WHERE i.page_id = $1 ORDER BY i.position`
+
+	rows, err := r.db.db.QueryContext(ctx, query, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list page items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []entity.PageItem{}
+	for rows.Next() {
+		var item entity.PageItem
+		if err := rows.Scan(&item.ShortURL, &item.Title, &item.Icon); err != nil {
+			return nil, fmt.Errorf("failed to scan page item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list page items: %w", err)
+	}
+
+	return items, nil
+}
+
+// insertPageItems сохраняет пункты страницы в порядке следования
+func insertPageItems(ctx context.Context, tx *sql.Tx, page *entity.Page) error {
+	query := `INSERT INTO page_items (page_id, position, link_id, title, icon)
+			  SELECT $1, $2, id, $4, $5 FROM links WHERE short_url = $3`
+
+	for i, item := range page.Items {
+		result, err := tx.ExecContext(ctx, query, page.ID, i, item.ShortURL, item.Title, item.Icon)
+		if err != nil {
+			return fmt.Errorf("failed to create page item: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return fmt.Errorf("failed to create page item: link %q not found", item.ShortURL)
+		}
+	}
+	return nil
+}
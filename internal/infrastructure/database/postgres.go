@@ -3,10 +3,11 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/oziev02/Shortener/internal/domain/entity"
 	"github.com/oziev02/Shortener/internal/domain/repository"
 )
@@ -56,6 +57,148 @@ func (p *PostgresDB) migrate() error {
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_clicks_link_id ON clicks(link_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_clicks_clicked_at ON clicks(clicked_at)`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS owner VARCHAR(255) NOT NULL DEFAULT ''`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS canonical_url TEXT`,
+		`UPDATE links SET canonical_url = original_url WHERE canonical_url IS NULL`,
+		`DROP INDEX IF EXISTS idx_links_owner_original_url`,
+		`CREATE INDEX IF NOT EXISTS idx_links_owner_canonical_url ON links(owner, md5(canonical_url))`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS password_hash TEXT`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS require_signature BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS interstitial_seconds INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS targeting_rules JSONB`,
+		`ALTER TABLE clicks ADD COLUMN IF NOT EXISTS matched_rule VARCHAR(64)`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS geo_rules JSONB`,
+		`ALTER TABLE clicks ADD COLUMN IF NOT EXISTS country VARCHAR(2)`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS variants JSONB`,
+		`ALTER TABLE clicks ADD COLUMN IF NOT EXISTS variant VARCHAR(32)`,
+		`CREATE TABLE IF NOT EXISTS conversions (
+			id SERIAL PRIMARY KEY,
+			link_id INTEGER NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+			variant VARCHAR(32) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_conversions_link_id ON conversions(link_id)`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS rules JSONB`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS active_from TIMESTAMP`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS active_until TIMESTAMP`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS prelaunch_url TEXT`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1`,
+		`CREATE TABLE IF NOT EXISTS link_revisions (
+			id SERIAL PRIMARY KEY,
+			link_id INTEGER NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+			revision INTEGER NOT NULL,
+			action VARCHAR(16) NOT NULL,
+			restored_from INTEGER,
+			state JSONB NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			UNIQUE (link_id, revision)
+		)`,
+		// Ссылки, созданные до появления истории, получают исходную ревизию с текущим состоянием
+		`INSERT INTO link_revisions (link_id, revision, action, state, created_at)
+		 SELECT l.id, l.revision, 'create', jsonb_strip_nulls(jsonb_build_object(
+			'original_url', l.original_url,
+			'canonical_url', COALESCE(l.canonical_url, l.original_url),
+			'interstitial_seconds', l.interstitial_seconds,
+			'targeting_rules', l.targeting_rules,
+			'geo_rules', l.geo_rules,
+			'rules', l.rules,
+			'variants', l.variants,
+			'active_from', to_char(l.active_from, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+			'active_until', to_char(l.active_until, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+			'prelaunch_url', l.prelaunch_url
+		 )), l.created_at
+		 FROM links l
+		 WHERE NOT EXISTS (SELECT 1 FROM link_revisions r WHERE r.link_id = l.id)`,
+		`ALTER TABLE clicks ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1`,
+		`CREATE TABLE IF NOT EXISTS campaigns (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(64) UNIQUE NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS campaign VARCHAR(64)
+			REFERENCES campaigns(name) ON UPDATE CASCADE ON DELETE SET NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_links_campaign ON links(campaign)`,
+		`CREATE TABLE IF NOT EXISTS tags (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(64) UNIQUE NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS link_tags (
+			link_id INTEGER NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+			tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+			PRIMARY KEY (link_id, tag_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_link_tags_tag_id ON link_tags(tag_id)`,
+		`CREATE TABLE IF NOT EXISTS utm_templates (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(64) UNIQUE NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			utm_source VARCHAR(256) NOT NULL DEFAULT '',
+			utm_medium VARCHAR(256) NOT NULL DEFAULT '',
+			utm_campaign VARCHAR(256) NOT NULL DEFAULT '',
+			utm_term VARCHAR(256) NOT NULL DEFAULT '',
+			utm_content VARCHAR(256) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)`,
+		`ALTER TABLE clicks ADD COLUMN IF NOT EXISTS utm_source VARCHAR(256)`,
+		`ALTER TABLE clicks ADD COLUMN IF NOT EXISTS utm_medium VARCHAR(256)`,
+		`ALTER TABLE clicks ADD COLUMN IF NOT EXISTS utm_campaign VARCHAR(256)`,
+		`ALTER TABLE clicks ADD COLUMN IF NOT EXISTS utm_term VARCHAR(256)`,
+		`ALTER TABLE clicks ADD COLUMN IF NOT EXISTS utm_content VARCHAR(256)`,
+		`CREATE INDEX IF NOT EXISTS idx_clicks_utm_campaign ON clicks(utm_campaign)`,
+		`CREATE TABLE IF NOT EXISTS pages (
+			id SERIAL PRIMARY KEY,
+			slug VARCHAR(64) UNIQUE NOT NULL,
+			title TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE TABLE IF NOT EXISTS page_items (
+			page_id INTEGER NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			link_id INTEGER NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+			title TEXT NOT NULL,
+			icon TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (page_id, position)
+		)`,
+		// Просмотр страницы записывается переходом без ссылки
+		`ALTER TABLE clicks ALTER COLUMN link_id DROP NOT NULL`,
+		`ALTER TABLE clicks ADD COLUMN IF NOT EXISTS page_id INTEGER REFERENCES pages(id) ON DELETE SET NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_clicks_page_id ON clicks(page_id)`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS health JSONB`,
+		`CREATE TABLE IF NOT EXISTS health_checks (
+			id SERIAL PRIMARY KEY,
+			link_id INTEGER NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+			healthy BOOLEAN NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			method VARCHAR(8) NOT NULL DEFAULT '',
+			redirects JSONB,
+			final_url TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT '',
+			duration_ms BIGINT NOT NULL DEFAULT 0,
+			checked_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_health_checks_link_id ON health_checks(link_id, checked_at DESC)`,
+		`CREATE TABLE IF NOT EXISTS health_subscriptions (
+			id SERIAL PRIMARY KEY,
+			owner VARCHAR(255) NOT NULL DEFAULT '',
+			webhook_url TEXT NOT NULL,
+			secret TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_health_subscriptions_owner ON health_subscriptions(owner)`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS open_graph JSONB`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS fetched_open_graph JSONB`,
+		`ALTER TABLE links ADD COLUMN IF NOT EXISTS deep_link JSONB`,
+		`CREATE TABLE IF NOT EXISTS domain_rules (
+			id SERIAL PRIMARY KEY,
+			action VARCHAR(16) NOT NULL,
+			kind VARCHAR(16) NOT NULL,
+			pattern TEXT NOT NULL,
+			comment TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
 	}
 
 	for _, query := range queries {
@@ -82,72 +225,301 @@ func NewLinkRepository(db *PostgresDB) repository.LinkRepository {
 	return &LinkRepositoryImpl{db: db}
 }
 
+// linkColumns список колонок таблицы links в порядке, ожидаемом scanLink
+const linkColumns = `id, short_url, original_url, COALESCE(canonical_url, original_url), custom_alias, owner,
+	COALESCE(password_hash, ''), require_signature, interstitial_seconds, targeting_rules, geo_rules,
+	rules, variants, active_from, active_until, COALESCE(prelaunch_url, ''), revision, COALESCE(campaign, ''),
+	ARRAY(SELECT t.name FROM link_tags lt JOIN tags t ON t.id = lt.tag_id WHERE lt.link_id = links.id ORDER BY t.name),
+	open_graph, fetched_open_graph, deep_link, health, created_at`
+
+// nullString преобразует пустую строку в NULL
+func nullString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// marshalJSONColumn сериализует значение для колонки JSONB; при empty записывается NULL
+func marshalJSONColumn(value interface{}, empty bool) (interface{}, error) {
+	if empty {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// unmarshalJSONColumn разбирает колонку JSONB; NULL оставляет dest без изменений
+func unmarshalJSONColumn(data []byte, dest interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanLink считывает ссылку из строки результата запроса
+func scanLink(row rowScanner) (*entity.Link, error) {
+	link := &entity.Link{}
+	var customAlias sql.NullString
+	var activeFrom, activeUntil sql.NullTime
+	var tags pq.StringArray
+	var targetingRules, geoRules, rules, variants, openGraph, fetchedOpenGraph, deepLink, health []byte
+	if err := row.Scan(
+		&link.ID,
+		&link.ShortURL,
+		&link.OriginalURL,
+		&link.CanonicalURL,
+		&customAlias,
+		&link.Owner,
+		&link.PasswordHash,
+		&link.RequireSignature,
+		&link.InterstitialSeconds,
+		&targetingRules,
+		&geoRules,
+		&rules,
+		&variants,
+		&activeFrom,
+		&activeUntil,
+		&link.PrelaunchURL,
+		&link.Revision,
+		&link.Campaign,
+		&tags,
+		&openGraph,
+		&fetchedOpenGraph,
+		&deepLink,
+		&health,
+		&link.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalJSONColumn(targetingRules, &link.TargetingRules); err != nil {
+		return nil, fmt.Errorf("failed to decode targeting rules: %w", err)
+	}
+	if err := unmarshalJSONColumn(geoRules, &link.GeoRules); err != nil {
+		return nil, fmt.Errorf("failed to decode geo rules: %w", err)
+	}
+	if err := unmarshalJSONColumn(rules, &link.Rules); err != nil {
+		return nil, fmt.Errorf("failed to decode routing rules: %w", err)
+	}
+	if err := unmarshalJSONColumn(variants, &link.Variants); err != nil {
+		return nil, fmt.Errorf("failed to decode variants: %w", err)
+	}
+	if err := unmarshalJSONColumn(openGraph, &link.OpenGraph); err != nil {
+		return nil, fmt.Errorf("failed to decode open graph: %w", err)
+	}
+	if err := unmarshalJSONColumn(fetchedOpenGraph, &link.FetchedOpenGraph); err != nil {
+		return nil, fmt.Errorf("failed to decode fetched open graph: %w", err)
+	}
+	if err := unmarshalJSONColumn(deepLink, &link.DeepLink); err != nil {
+		return nil, fmt.Errorf("failed to decode deep link: %w", err)
+	}
+	if err := unmarshalJSONColumn(health, &link.Health); err != nil {
+		return nil, fmt.Errorf("failed to decode link health: %w", err)
+	}
+
+	link.Protected = link.PasswordHash != ""
+	if len(tags) > 0 {
+		link.Tags = tags
+	}
+
+	// Границы окна записываются в UTC
+	if activeFrom.Valid {
+		t := activeFrom.Time.UTC()
+		link.ActiveFrom = &t
+	}
+	if activeUntil.Valid {
+		t := activeUntil.Time.UTC()
+		link.ActiveUntil = &t
+	}
+
+	if customAlias.Valid {
+		link.CustomAlias = customAlias.String
+	}
+
+	return link, nil
+}
+
+// linkStateColumns колонки изменяемых параметров ссылки в порядке, возвращаемом linkStateArgs
+const linkStateColumns = `original_url, canonical_url, interstitial_seconds, targeting_rules, geo_rules, rules,
+	variants, active_from, active_until, prelaunch_url, open_graph, deep_link`
+
+// linkStateArgs возвращает значения изменяемых параметров ссылки для INSERT и UPDATE
+func linkStateArgs(link *entity.Link) ([]interface{}, error) {
+	targetingRules, err := marshalJSONColumn(link.TargetingRules, len(link.TargetingRules) == 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode targeting rules: %w", err)
+	}
+	geoRules, err := marshalJSONColumn(link.GeoRules, len(link.GeoRules) == 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode geo rules: %w", err)
+	}
+	rules, err := marshalJSONColumn(link.Rules, len(link.Rules) == 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode routing rules: %w", err)
+	}
+	variants, err := marshalJSONColumn(link.Variants, len(link.Variants) == 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode variants: %w", err)
+	}
+	openGraph, err := marshalJSONColumn(link.OpenGraph, link.OpenGraph == nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode open graph: %w", err)
+	}
+	deepLink, err := marshalJSONColumn(link.DeepLink, link.DeepLink == nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode deep link: %w", err)
+	}
+	// Пустой адрес до запуска храним как NULL
+	var prelaunchURL interface{} = link.PrelaunchURL
+	if link.PrelaunchURL == "" {
+		prelaunchURL = nil
+	}
+
+	return []interface{}{
+		link.OriginalURL,
+		link.CanonicalURL,
+		link.InterstitialSeconds,
+		targetingRules,
+		geoRules,
+		rules,
+		variants,
+		link.ActiveFrom,
+		link.ActiveUntil,
+		prelaunchURL,
+		openGraph,
+		deepLink,
+	}, nil
+}
+
+// Create сохраняет ссылку вместе с тегами и первой ревизией
 func (r *LinkRepositoryImpl) Create(ctx context.Context, link *entity.Link) error {
-	query := `INSERT INTO links (short_url, original_url, custom_alias, created_at) 
-			  VALUES ($1, $2, $3, $4) RETURNING id`
+	query := `INSERT INTO links (short_url, custom_alias, owner, password_hash, require_signature, campaign, created_at,
+			  ` + linkStateColumns + `) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19) RETURNING id`
 
-	// Преобразуем пустую строку в NULL для custom_alias
+	// Преобразуем пустую строку в NULL для custom_alias, password_hash и campaign
 	var customAlias interface{} = link.CustomAlias
 	if link.CustomAlias == "" {
 		customAlias = nil
 	}
-
-	err := r.db.db.QueryRowContext(ctx, query,
+	var passwordHash interface{} = link.PasswordHash
+	if link.PasswordHash == "" {
+		passwordHash = nil
+	}
+	var campaign interface{} = link.Campaign
+	if link.Campaign == "" {
+		campaign = nil
+	}
+	stateArgs, err := linkStateArgs(link)
+	if err != nil {
+		return err
+	}
+	args := append([]interface{}{
 		link.ShortURL,
-		link.OriginalURL,
 		customAlias,
+		link.Owner,
+		passwordHash,
+		link.RequireSignature,
+		campaign,
 		link.CreatedAt,
-	).Scan(&link.ID)
+	}, stateArgs...)
 
+	tx, err := r.db.db.BeginTx(ctx, nil)
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&link.ID); err != nil {
 		return fmt.Errorf("failed to create link: %w", err)
 	}
 
+	if err := replaceLinkTags(ctx, tx, link.ID, link.Tags); err != nil {
+		return err
+	}
+
+	link.Revision = 1
+	revision := &entity.LinkRevision{Action: entity.RevisionCreate, CreatedAt: link.CreatedAt}
+	if err := insertRevision(ctx, tx, link, revision); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit link: %w", err)
+	}
+
 	return nil
 }
 
-func (r *LinkRepositoryImpl) GetByShortURL(ctx context.Context, shortURL string) (*entity.Link, error) {
-	query := `SELECT id, short_url, original_url, custom_alias, created_at 
-			  FROM links WHERE short_url = $1`
+// Update сохраняет изменяемые параметры ссылки и новую ревизию в одной транзакции
+func (r *LinkRepositoryImpl) Update(ctx context.Context, link *entity.Link, revision *entity.LinkRevision) (bool, error) {
+	query := `UPDATE links SET (` + linkStateColumns + `, revision) =
+			  ($2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, revision + 1)
+			  WHERE id = $1 AND revision = $14
+			  RETURNING revision`
 
-	link := &entity.Link{}
-	var customAlias sql.NullString
-	err := r.db.db.QueryRowContext(ctx, query, shortURL).Scan(
-		&link.ID,
-		&link.ShortURL,
-		&link.OriginalURL,
-		&customAlias,
-		&link.CreatedAt,
-	)
+	stateArgs, err := linkStateArgs(link)
+	if err != nil {
+		return false, err
+	}
+	args := append([]interface{}{link.ID}, stateArgs...)
+	args = append(args, link.Revision)
 
+	tx, err := r.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var newRevision int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&newRevision)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		// Ссылку изменили параллельно или удалили
+		return false, nil
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get link: %w", err)
+		return false, fmt.Errorf("failed to update link: %w", err)
 	}
 
-	if customAlias.Valid {
-		link.CustomAlias = customAlias.String
+	link.Revision = newRevision
+	if err := insertRevision(ctx, tx, link, revision); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit link update: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *LinkRepositoryImpl) GetByShortURL(ctx context.Context, shortURL string) (*entity.Link, error) {
+	query := `SELECT ` + linkColumns + ` FROM links WHERE short_url = $1`
+
+	link, err := scanLink(r.db.db.QueryRowContext(ctx, query, shortURL))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
 	}
 
 	return link, nil
 }
 
 func (r *LinkRepositoryImpl) GetByCustomAlias(ctx context.Context, alias string) (*entity.Link, error) {
-	query := `SELECT id, short_url, original_url, custom_alias, created_at 
-			  FROM links WHERE custom_alias = $1`
-
-	link := &entity.Link{}
-	var customAlias sql.NullString
-	err := r.db.db.QueryRowContext(ctx, query, alias).Scan(
-		&link.ID,
-		&link.ShortURL,
-		&link.OriginalURL,
-		&customAlias,
-		&link.CreatedAt,
-	)
+	query := `SELECT ` + linkColumns + ` FROM links WHERE custom_alias = $1`
 
+	link, err := scanLink(r.db.db.QueryRowContext(ctx, query, alias))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -155,13 +527,116 @@ func (r *LinkRepositoryImpl) GetByCustomAlias(ctx context.Context, alias string)
 		return nil, fmt.Errorf("failed to get link by alias: %w", err)
 	}
 
-	if customAlias.Valid {
-		link.CustomAlias = customAlias.String
+	return link, nil
+}
+
+func (r *LinkRepositoryImpl) GetByOwnerAndCanonicalURL(ctx context.Context, owner, canonicalURL string) (*entity.Link, error) {
+	// md5 позволяет использовать индекс, не ограничивая длину URL
+	query := `SELECT ` + linkColumns + ` FROM links
+			  WHERE owner = $1 AND md5(canonical_url) = md5($2) AND canonical_url = $2
+			  ORDER BY id LIMIT 1`
+
+	link, err := scanLink(r.db.db.QueryRowContext(ctx, query, owner, canonicalURL))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link by owner and URL: %w", err)
 	}
 
 	return link, nil
 }
 
+func (r *LinkRepositoryImpl) List(ctx context.Context, filter repository.LinkFilter) ([]*entity.Link, error) {
+	// Пустые значения фильтров не ограничивают выборку
+	query := `SELECT ` + linkColumns + ` FROM links
+			  WHERE ($3::text = '' OR campaign = $3)
+			    AND ($4::text = '' OR EXISTS (SELECT 1 FROM link_tags lt JOIN tags t ON t.id = lt.tag_id
+			                            WHERE lt.link_id = links.id AND t.name = $4))
+			  ORDER BY id LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.db.QueryContext(ctx, query, filter.Limit, filter.Offset, filter.Campaign, filter.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*entity.Link
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan link: %w", err)
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list links: %w", err)
+	}
+
+	return links, nil
+}
+
+func (r *LinkRepositoryImpl) SetTags(ctx context.Context, linkID int64, tags []string) error {
+	tx, err := r.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceLinkTags(ctx, tx, linkID, tags); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit link tags: %w", err)
+	}
+	return nil
+}
+
+func (r *LinkRepositoryImpl) SetCampaign(ctx context.Context, linkID int64, campaign string) error {
+	var value interface{} = campaign
+	if campaign == "" {
+		value = nil
+	}
+
+	if _, err := r.db.db.ExecContext(ctx, `UPDATE links SET campaign = $2 WHERE id = $1`, linkID, value); err != nil {
+		return fmt.Errorf("failed to set link campaign: %w", err)
+	}
+	return nil
+}
+
+func (r *LinkRepositoryImpl) SetFetchedOpenGraph(ctx context.Context, linkID int64, og *entity.OpenGraph) error {
+	value, err := marshalJSONColumn(og, og == nil)
+	if err != nil {
+		return fmt.Errorf("failed to encode fetched open graph: %w", err)
+	}
+
+	if _, err := r.db.db.ExecContext(ctx, `UPDATE links SET fetched_open_graph = $2 WHERE id = $1`, linkID, value); err != nil {
+		return fmt.Errorf("failed to set fetched open graph: %w", err)
+	}
+	return nil
+}
+
+// replaceLinkTags заменяет теги ссылки, создавая отсутствующие
+func replaceLinkTags(ctx context.Context, tx *sql.Tx, linkID int64, tags []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM link_tags WHERE link_id = $1`, linkID); err != nil {
+		return fmt.Errorf("failed to clear link tags: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`,
+		pq.Array(tags)); err != nil {
+		return fmt.Errorf("failed to create tags: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO link_tags (link_id, tag_id)
+			  SELECT $1, id FROM tags WHERE name = ANY($2)`, linkID, pq.Array(tags)); err != nil {
+		return fmt.Errorf("failed to set link tags: %w", err)
+	}
+	return nil
+}
+
 func (r *LinkRepositoryImpl) Exists(ctx context.Context, shortURL string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM links WHERE short_url = $1)`
 	var exists bool
@@ -172,6 +647,36 @@ func (r *LinkRepositoryImpl) Exists(ctx context.Context, shortURL string) (bool,
 	return exists, nil
 }
 
+func (r *LinkRepositoryImpl) TakenAliases(ctx context.Context, aliases []string) ([]string, error) {
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT short_url FROM links WHERE short_url = ANY($1)
+			  UNION
+			  SELECT custom_alias FROM links WHERE custom_alias = ANY($1)`
+
+	rows, err := r.db.db.QueryContext(ctx, query, pq.Array(aliases))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var taken []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		taken = append(taken, alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check aliases: %w", err)
+	}
+
+	return taken, nil
+}
+
 // ClickRepositoryImpl реализует repository.ClickRepository
 type ClickRepositoryImpl struct {
 	db *PostgresDB
@@ -183,13 +688,52 @@ func NewClickRepository(db *PostgresDB) repository.ClickRepository {
 }
 
 func (r *ClickRepositoryImpl) Create(ctx context.Context, click *entity.Click) error {
-	query := `INSERT INTO clicks (link_id, user_agent, ip_address, clicked_at) 
-			  VALUES ($1, $2, $3, $4) RETURNING id`
+	query := `INSERT INTO clicks (link_id, user_agent, ip_address, matched_rule, country, variant, revision,
+			  utm_source, utm_medium, utm_campaign, utm_term, utm_content, page_id, clicked_at) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`
+
+	// Просмотр страницы не относится к ссылке
+	var linkID interface{} = click.LinkID
+	if click.LinkID == 0 {
+		linkID = nil
+	}
+	var pageID interface{} = click.PageID
+	if click.PageID == 0 {
+		pageID = nil
+	}
+
+	// Пустое правило означает переход на основной адрес
+	var matchedRule interface{} = click.MatchedRule
+	if click.MatchedRule == "" {
+		matchedRule = nil
+	}
+	var country interface{} = click.Country
+	if click.Country == "" {
+		country = nil
+	}
+	var variant interface{} = click.Variant
+	if click.Variant == "" {
+		variant = nil
+	}
+	var utm entity.UTMParams
+	if click.UTM != nil {
+		utm = *click.UTM
+	}
 
 	err := r.db.db.QueryRowContext(ctx, query,
-		click.LinkID,
+		linkID,
 		click.UserAgent,
 		click.IPAddress,
+		matchedRule,
+		country,
+		variant,
+		click.Revision,
+		nullString(utm.Source),
+		nullString(utm.Medium),
+		nullString(utm.Campaign),
+		nullString(utm.Term),
+		nullString(utm.Content),
+		pageID,
 		click.ClickedAt,
 	).Scan(&click.ID)
 
@@ -282,11 +826,79 @@ func (r *ClickRepositoryImpl) GetAnalytics(ctx context.Context, linkID int64) (*
 		analytics.ByUserAgent[ua] = count
 	}
 
+	// Группировка по вариантам A/B-теста
+	variantQuery := `SELECT variant, COUNT(*) as count 
+					 FROM clicks WHERE link_id = $1 AND variant IS NOT NULL
+					 GROUP BY variant`
+	rows, err = r.db.db.QueryContext(ctx, variantQuery, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clicks by variant: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var variant string
+		var count int64
+		if err := rows.Scan(&variant, &count); err != nil {
+			continue
+		}
+		if analytics.ByVariant == nil {
+			analytics.ByVariant = make(map[string]*entity.VariantStats)
+		}
+		analytics.ByVariant[variant] = &entity.VariantStats{Clicks: count}
+	}
+
+	// Группировка по ревизиям ссылки
+	revisionQuery := `SELECT revision, COUNT(*) as count 
+					  FROM clicks WHERE link_id = $1
+					  GROUP BY revision`
+	rows, err = r.db.db.QueryContext(ctx, revisionQuery, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clicks by revision: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var revision int
+		var count int64
+		if err := rows.Scan(&revision, &count); err != nil {
+			continue
+		}
+		if analytics.ByRevision == nil {
+			analytics.ByRevision = make(map[int]int64)
+		}
+		analytics.ByRevision[revision] = count
+	}
+
+	// Группировка по utm_campaign адреса назначения
+	utmQuery := `SELECT utm_campaign, COUNT(*) as count 
+				 FROM clicks WHERE link_id = $1 AND utm_campaign IS NOT NULL
+				 GROUP BY utm_campaign`
+	rows, err = r.db.db.QueryContext(ctx, utmQuery, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clicks by utm campaign: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var campaign string
+		var count int64
+		if err := rows.Scan(&campaign, &count); err != nil {
+			continue
+		}
+		if analytics.ByUTMCampaign == nil {
+			analytics.ByUTMCampaign = make(map[string]int64)
+		}
+		analytics.ByUTMCampaign[campaign] = count
+	}
+
 	return analytics, nil
 }
 
 func (r *ClickRepositoryImpl) GetByLinkID(ctx context.Context, linkID int64, limit int) ([]*entity.Click, error) {
-	query := `SELECT id, link_id, user_agent, ip_address, clicked_at 
+	query := `SELECT id, link_id, user_agent, ip_address, COALESCE(matched_rule, ''), COALESCE(country, ''), COALESCE(variant, ''),
+			  revision, COALESCE(utm_source, ''), COALESCE(utm_medium, ''), COALESCE(utm_campaign, ''),
+			  COALESCE(utm_term, ''), COALESCE(utm_content, ''), COALESCE(page_id, 0), clicked_at 
 			  FROM clicks WHERE link_id = $1 
 			  ORDER BY clicked_at DESC LIMIT $2`
 
@@ -299,17 +911,72 @@ func (r *ClickRepositoryImpl) GetByLinkID(ctx context.Context, linkID int64, lim
 	var clicks []*entity.Click
 	for rows.Next() {
 		click := &entity.Click{}
+		var utm entity.UTMParams
 		if err := rows.Scan(
 			&click.ID,
 			&click.LinkID,
 			&click.UserAgent,
 			&click.IPAddress,
+			&click.MatchedRule,
+			&click.Country,
+			&click.Variant,
+			&click.Revision,
+			&utm.Source,
+			&utm.Medium,
+			&utm.Campaign,
+			&utm.Term,
+			&utm.Content,
+			&click.PageID,
 			&click.ClickedAt,
 		); err != nil {
 			continue
 		}
+		if !utm.IsZero() {
+			click.UTM = &utm
+		}
 		clicks = append(clicks, click)
 	}
 
 	return clicks, nil
 }
+
+// utmColumns колонки таблицы clicks по именам UTM-меток из entity.UTMFields
+var utmColumns = map[string]string{
+	"source":   "utm_source",
+	"medium":   "utm_medium",
+	"campaign": "utm_campaign",
+	"term":     "utm_term",
+	"content":  "utm_content",
+}
+
+func (r *ClickRepositoryImpl) GetUTMReport(ctx context.Context, field string) ([]*entity.UTMReportRow, error) {
+	column, ok := utmColumns[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown utm field %q", field)
+	}
+
+	// Имя колонки берётся только из utmColumns, поэтому подстановка в запрос безопасна
+	query := `SELECT ` + column + `, COUNT(DISTINCT link_id), COUNT(*) as count
+			  FROM clicks WHERE ` + column + ` IS NOT NULL
+			  GROUP BY ` + column + ` ORDER BY count DESC, ` + column
+
+	rows, err := r.db.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get utm report: %w", err)
+	}
+	defer rows.Close()
+
+	var report []*entity.UTMReportRow
+	for rows.Next() {
+		row := &entity.UTMReportRow{}
+		if err := rows.Scan(&row.Value, &row.Links, &row.Clicks); err != nil {
+			return nil, fmt.Errorf("failed to scan utm report: %w", err)
+		}
+		report = append(report, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get utm report: %w", err)
+	}
+
+	return report, nil
+}
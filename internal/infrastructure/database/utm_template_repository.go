@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/repository"
+)
+
+// UTMTemplateRepositoryImpl реализует repository.UTMTemplateRepository
+type UTMTemplateRepositoryImpl struct {
+	db *PostgresDB
+}
+
+// NewUTMTemplateRepository создаёт новый репозиторий шаблонов UTM-меток
+func NewUTMTemplateRepository(db *PostgresDB) repository.UTMTemplateRepository {
+	return &UTMTemplateRepositoryImpl{db: db}
+}
+
+// utmTemplateColumns список колонок таблицы utm_templates в порядке, ожидаемом scanUTMTemplate
+const utmTemplateColumns = `id, name, description, utm_source, utm_medium, utm_campaign, utm_term, utm_content, created_at`
+
+// scanUTMTemplate считывает шаблон из строки результата запроса
+func scanUTMTemplate(row rowScanner) (*entity.UTMTemplate, error) {
+	template := &entity.UTMTemplate{}
+	if err := row.Scan(
+		&template.ID,
+		&template.Name,
+		&template.Description,
+		&template.Source,
+		&template.Medium,
+		&template.Campaign,
+		&template.Term,
+		&template.Content,
+		&template.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (r *UTMTemplateRepositoryImpl) Create(ctx context.Context, template *entity.UTMTemplate) error {
+	query := `INSERT INTO utm_templates (name, description, utm_source, utm_medium, utm_campaign, utm_term, utm_content, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+
+	err := r.db.db.QueryRowContext(ctx, query,
+		template.Name,
+		template.Description,
+		template.Source,
+		template.Medium,
+		template.Campaign,
+		template.Term,
+		template.Content,
+		template.CreatedAt,
+	).Scan(&template.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create utm template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UTMTemplateRepositoryImpl) GetByName(ctx context.Context, name string) (*entity.UTMTemplate, error) {
+	query := `SELECT ` + utmTemplateColumns + ` FROM utm_templates WHERE name = $1`
+
+	template, err := scanUTMTemplate(r.db.db.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get utm template: %w", err)
+	}
+
+	return template, nil
+}
+
+func (r *UTMTemplateRepositoryImpl) List(ctx context.Context) ([]*entity.UTMTemplate, error) {
+	query := `SELECT ` + utmTemplateColumns + ` FROM utm_templates ORDER BY name`
+
+	rows, err := r.db.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list utm templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*entity.UTMTemplate
+	for rows.Next() {
+		template, err := scanUTMTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan utm template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list utm templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+func (r *UTMTemplateRepositoryImpl) Update(ctx context.Context, template *entity.UTMTemplate) (bool, error) {
+	query := `UPDATE utm_templates
+			  SET description = $2, utm_source = $3, utm_medium = $4, utm_campaign = $5, utm_term = $6, utm_content = $7
+			  WHERE name = $1`
+
+	result, err := r.db.db.ExecContext(ctx, query,
+		template.Name,
+		template.Description,
+		template.Source,
+		template.Medium,
+		template.Campaign,
+		template.Term,
+		template.Content,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update utm template: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update utm template: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *UTMTemplateRepositoryImpl) Delete(ctx context.Context, name string) (bool, error) {
+	result, err := r.db.db.ExecContext(ctx, `DELETE FROM utm_templates WHERE name = $1`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete utm template: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete utm template: %w", err)
+	}
+
+	return affected > 0, nil
+}
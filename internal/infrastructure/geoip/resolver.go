@@ -0,0 +1,131 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+)
+
+// ipRange диапазон адресов одной сети
+type ipRange struct {
+	start    net.IP
+	end      net.IP
+	location usecase.GeoLocation
+}
+
+// Resolver определяет страну и континент по IP-адресу по локальной таблице сетей.
+// Таблица загружается целиком в память, поиск выполняется бинарным поиском.
+type Resolver struct {
+	v4 []ipRange
+	v6 []ipRange
+}
+
+// LoadCSV читает таблицу сетей из файла (см. Parse)
+func LoadCSV(path string) (*Resolver, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Parse(file)
+}
+
+// Parse читает таблицу сетей в формате CSV: network,country,continent.
+// network — CIDR, country — код ISO 3166-1 alpha-2, continent — двухбуквенный код континента.
+// Строки, начинающиеся с "#", и строка заголовка пропускаются. Сети не должны пересекаться.
+func Parse(r io.Reader) (*Resolver, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	resolver := &Resolver{}
+	line := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if line == 1 && strings.EqualFold(record[0], "network") {
+			continue
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("line %d: expected network,country,continent", line)
+		}
+
+		_, network, err := net.ParseCIDR(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		entry := ipRange{
+			start: network.IP,
+			end:   lastIP(network),
+			location: usecase.GeoLocation{
+				Country:   strings.ToUpper(strings.TrimSpace(record[1])),
+				Continent: strings.ToUpper(strings.TrimSpace(record[2])),
+			},
+		}
+		if ip4 := network.IP.To4(); ip4 != nil {
+			entry.start, entry.end = ip4, entry.end.To4()
+			resolver.v4 = append(resolver.v4, entry)
+		} else {
+			resolver.v6 = append(resolver.v6, entry)
+		}
+	}
+
+	sortRanges(resolver.v4)
+	sortRanges(resolver.v6)
+	return resolver, nil
+}
+
+// Lookup возвращает местоположение IP-адреса
+func (r *Resolver) Lookup(ipAddress string) (usecase.GeoLocation, bool) {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return usecase.GeoLocation{}, false
+	}
+
+	ranges := r.v6
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, ranges = ip4, r.v4
+	}
+
+	// Ищем последний диапазон, начинающийся не позже адреса
+	i := sort.Search(len(ranges), func(i int) bool {
+		return bytes.Compare(ranges[i].start, ip) > 0
+	}) - 1
+	if i < 0 || bytes.Compare(ip, ranges[i].end) > 0 {
+		return usecase.GeoLocation{}, false
+	}
+	return ranges[i].location, true
+}
+
+// sortRanges упорядочивает диапазоны по начальному адресу
+func sortRanges(ranges []ipRange) {
+	sort.Slice(ranges, func(i, j int) bool {
+		return bytes.Compare(ranges[i].start, ranges[j].start) < 0
+	})
+}
+
+// lastIP возвращает последний адрес сети
+func lastIP(network *net.IPNet) net.IP {
+	last := make(net.IP, len(network.IP))
+	for i := range network.IP {
+		last[i] = network.IP[i] | ^network.Mask[i]
+	}
+	return last
+}
@@ -0,0 +1,196 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+const (
+	// userAgent заголовок User-Agent запросов проверки
+	userAgent = "ShortenerHealthCheck/1.0"
+
+	// maxRedirects максимальная длина цепочки редиректов
+	maxRedirects = 10
+
+	// maxBodyRead объём тела ответа GET, который дочитывается перед закрытием соединения
+	maxBodyRead = 64 << 10
+)
+
+// hostSlot очередь запросов к одному хосту
+type hostSlot struct {
+	lock chan struct{}
+	next time.Time
+}
+
+// Prober проверяет доступность адресов ссылок. Редиректы проходятся вручную, чтобы
+// каждый адрес цепочки проверялся политикой SSRF. Запросы к одному хосту выполняются
+// по одному и не чаще раза в hostDelay.
+type Prober struct {
+	client    *http.Client
+	policy    *service.URLPolicy
+	hostDelay time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostSlot
+}
+
+var _ usecase.HealthProber = (*Prober)(nil)
+
+// NewProber создаёт проверку доступности. Если client равен nil, используется клиент
+// по умолчанию; таймаут запроса задаётся в client.Timeout. Проверка URL политикой не видит,
+// куда на самом деле разрешится имя, поэтому в работе нужен клиент из httpclient.New.
+func NewProber(client *http.Client, policy *service.URLPolicy, hostDelay time.Duration) *Prober {
+	var c http.Client
+	if client != nil {
+		c = *client
+	}
+	c.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &Prober{
+		client:    &c,
+		policy:    policy,
+		hostDelay: hostDelay,
+		hosts:     make(map[string]*hostSlot),
+	}
+}
+
+// Probe выполняет проверку адреса. Сначала отправляется HEAD; если сервер отвечает
+// ошибкой (некоторые не поддерживают HEAD), запрос повторяется методом GET.
+func (p *Prober) Probe(ctx context.Context, rawURL string) entity.HealthCheck {
+	start := time.Now()
+	check := p.follow(ctx, http.MethodHead, rawURL)
+	if check.Error == "" && check.StatusCode >= http.StatusBadRequest {
+		check = p.follow(ctx, http.MethodGet, rawURL)
+	}
+
+	check.Healthy = check.Error == "" && isHealthyStatus(check.StatusCode)
+	check.DurationMs = time.Since(start).Milliseconds()
+	check.CheckedAt = start
+	return check
+}
+
+// follow выполняет запрос и проходит по цепочке редиректов
+func (p *Prober) follow(ctx context.Context, method, rawURL string) entity.HealthCheck {
+	check := entity.HealthCheck{Method: method}
+
+	current := rawURL
+	for hop := 0; ; hop++ {
+		target, err := url.Parse(current)
+		if err != nil {
+			check.Error = fmt.Sprintf("invalid url: %v", err)
+			return check
+		}
+		if err := p.policy.Check(ctx, current); err != nil {
+			check.Error = err.Error()
+			return check
+		}
+
+		resp, err := p.do(ctx, method, target)
+		if err != nil {
+			check.Error = err.Error()
+			return check
+		}
+		if method == http.MethodGet {
+			// Дочитываем начало тела, чтобы соединение можно было переиспользовать
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyRead))
+		}
+		resp.Body.Close()
+
+		check.StatusCode = resp.StatusCode
+		check.FinalURL = current
+
+		location := resp.Header.Get("Location")
+		if !isRedirect(resp.StatusCode) || location == "" {
+			return check
+		}
+		if hop >= maxRedirects {
+			check.Error = fmt.Sprintf("stopped after %d redirects", maxRedirects)
+			return check
+		}
+
+		next, err := target.Parse(location)
+		if err != nil {
+			check.Error = fmt.Sprintf("invalid redirect location: %v", err)
+			return check
+		}
+		check.Redirects = append(check.Redirects, next.String())
+		current = next.String()
+	}
+}
+
+// do отправляет запрос, соблюдая очередь и паузу между запросами к хосту
+func (p *Prober) do(ctx context.Context, method string, target *url.URL) (*http.Response, error) {
+	slot := p.slot(target.Host)
+
+	select {
+	case slot.lock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() {
+		slot.next = time.Now().Add(p.hostDelay)
+		<-slot.lock
+	}()
+
+	if wait := time.Until(slot.next); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	return p.client.Do(req)
+}
+
+// slot возвращает очередь запросов к хосту
+func (p *Prober) slot(host string) *hostSlot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	slot, ok := p.hosts[host]
+	if !ok {
+		slot = &hostSlot{lock: make(chan struct{}, 1)}
+		p.hosts[host] = slot
+	}
+	return slot
+}
+
+// isRedirect сообщает, является ли код ответа редиректом с заголовком Location
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// isHealthyStatus сообщает, считается ли ответ признаком доступного адреса.
+// 401, 403 и 429 означают, что сервер работает, но ограничивает доступ проверке.
+func isHealthyStatus(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return true
+	}
+	return status > 0 && status < http.StatusBadRequest
+}
@@ -0,0 +1,248 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/service"
+	"github.com/oziev02/Shortener/internal/infrastructure/httpclient"
+)
+
+// fakeResolver разрешает имена по таблице
+type fakeResolver map[string]string
+
+func (r fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+}
+
+// dialServer возвращает клиента, который отправляет запросы к любому хосту на тестовый сервер
+func dialServer(server *httptest.Server) *http.Client {
+	addr := server.Listener.Addr().String()
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+	}
+}
+
+func TestProbeFollowsRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusMovedPermanently)
+		case "/c":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	check := NewProber(server.Client(), nil, 0).Probe(context.Background(), server.URL+"/a")
+
+	if !check.Healthy || check.StatusCode != http.StatusOK || check.Error != "" {
+		t.Fatalf("Probe = %+v, want healthy 200", check)
+	}
+	if check.Method != http.MethodHead {
+		t.Fatalf("Method = %q, want HEAD", check.Method)
+	}
+	want := []string{server.URL + "/b", server.URL + "/c"}
+	if strings.Join(check.Redirects, " ") != strings.Join(want, " ") {
+		t.Fatalf("Redirects = %v, want %v", check.Redirects, want)
+	}
+	if check.FinalURL != server.URL+"/c" {
+		t.Fatalf("FinalURL = %q, want %q", check.FinalURL, server.URL+"/c")
+	}
+}
+
+func TestProbeStopsRedirectLoop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	}))
+	defer server.Close()
+
+	check := NewProber(server.Client(), nil, 0).Probe(context.Background(), server.URL+"/loop")
+
+	if check.Healthy || !strings.Contains(check.Error, "redirects") {
+		t.Fatalf("Probe = %+v, want redirect limit error", check)
+	}
+	if len(check.Redirects) != maxRedirects {
+		t.Fatalf("len(Redirects) = %d, want %d", len(check.Redirects), maxRedirects)
+	}
+}
+
+func TestProbeChecksPolicyOnEveryHop(t *testing.T) {
+	var mu sync.Mutex
+	var hosts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hosts = append(hosts, r.Host)
+		mu.Unlock()
+		http.Redirect(w, r, "http://internal.test/admin", http.StatusFound)
+	}))
+	defer server.Close()
+
+	policy := service.NewURLPolicy(true, fakeResolver{
+		"public.test":   "93.184.216.34",
+		"internal.test": "10.0.0.1",
+	})
+	prober := NewProber(dialServer(server), policy, 0)
+
+	check := prober.Probe(context.Background(), "http://public.test/start")
+	if check.Healthy || !strings.Contains(check.Error, service.ErrPrivateTarget.Error()) {
+		t.Fatalf("Probe = %+v, want private target error", check)
+	}
+	if len(check.Redirects) != 1 || check.Redirects[0] != "http://internal.test/admin" {
+		t.Fatalf("Redirects = %v, want the blocked hop", check.Redirects)
+	}
+
+	check = prober.Probe(context.Background(), "http://internal.test/")
+	if check.Healthy || !strings.Contains(check.Error, service.ErrPrivateTarget.Error()) {
+		t.Fatalf("Probe = %+v, want private target error", check)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, host := range hosts {
+		if host == "internal.test" {
+			t.Fatalf("request reached blocked host: %v", hosts)
+		}
+	}
+}
+
+func TestProbeClassifiesStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		head       int
+		get        int
+		healthy    bool
+		wantStatus int
+		wantMethod string
+	}{
+		{"ok", http.StatusOK, http.StatusOK, true, http.StatusOK, http.MethodHead},
+		{"no content", http.StatusNoContent, http.StatusNoContent, true, http.StatusNoContent, http.MethodHead},
+		{"not found", http.StatusNotFound, http.StatusNotFound, false, http.StatusNotFound, http.MethodGet},
+		{"gone", http.StatusGone, http.StatusGone, false, http.StatusGone, http.MethodGet},
+		{"server error", http.StatusInternalServerError, http.StatusBadGateway, false, http.StatusBadGateway, http.MethodGet},
+		{"head not allowed", http.StatusMethodNotAllowed, http.StatusOK, true, http.StatusOK, http.MethodGet},
+		{"forbidden", http.StatusForbidden, http.StatusForbidden, true, http.StatusForbidden, http.MethodGet},
+		{"rate limited", http.StatusTooManyRequests, http.StatusTooManyRequests, true, http.StatusTooManyRequests, http.MethodGet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodHead {
+					w.WriteHeader(tt.head)
+					return
+				}
+				w.WriteHeader(tt.get)
+			}))
+			defer server.Close()
+
+			check := NewProber(server.Client(), nil, 0).Probe(context.Background(), server.URL)
+
+			if check.Healthy != tt.healthy || check.StatusCode != tt.wantStatus || check.Method != tt.wantMethod {
+				t.Fatalf("Probe = healthy %v, status %d, method %s; want %v, %d, %s",
+					check.Healthy, check.StatusCode, check.Method, tt.healthy, tt.wantStatus, tt.wantMethod)
+			}
+		})
+	}
+}
+
+func TestProbeTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.Timeout = 50 * time.Millisecond
+
+	check := NewProber(client, nil, 0).Probe(context.Background(), server.URL)
+
+	if check.Healthy || check.StatusCode != 0 || check.Error == "" {
+		t.Fatalf("Probe = %+v, want timeout error", check)
+	}
+}
+
+func TestProbeHostDelay(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	const delay = 100 * time.Millisecond
+	prober := NewProber(server.Client(), nil, delay)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prober.Probe(context.Background(), server.URL)
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(times) != 3 {
+		t.Fatalf("got %d requests, want 3", len(times))
+	}
+	for i := 1; i < len(times); i++ {
+		// Небольшой запас на округление таймеров
+		if gap := times[i].Sub(times[i-1]); gap < delay-10*time.Millisecond {
+			t.Fatalf("gap between requests %d and %d = %v, want at least %v", i-1, i, gap, delay)
+		}
+	}
+}
+
+func TestProbeBlocksDNSRebinding(t *testing.T) {
+	hit := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit <- struct{}{}
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	// При проверке URL имя разрешается в публичный адрес, при подключении — в loopback
+	policy := service.NewURLPolicy(true, fakeResolver{"rebind.test": "93.184.216.34"})
+	transport := &http.Transport{
+		DialContext: httpclient.NewDialer(policy, fakeResolver{"rebind.test": "127.0.0.1"}).DialContext,
+	}
+
+	check := NewProber(&http.Client{Transport: transport}, policy, 0).Probe(context.Background(), "http://rebind.test:"+port+"/")
+
+	if check.Healthy || !strings.Contains(check.Error, service.ErrPrivateTarget.Error()) {
+		t.Fatalf("Probe = %+v, want private target error", check)
+	}
+	select {
+	case <-hit:
+		t.Fatal("request reached loopback server")
+	default:
+	}
+}
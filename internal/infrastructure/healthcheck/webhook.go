@@ -0,0 +1,72 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// WebhookNotifier отправляет уведомления POST-запросом с телом entity.HealthAlert в JSON.
+// Если у подписки задан секрет, тело подписывается HMAC-SHA256 в заголовке
+// X-Shortener-Signature в виде "sha256=<hex>".
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+var _ usecase.HealthNotifier = (*WebhookNotifier)(nil)
+
+// NewWebhookNotifier создаёт отправителя уведомлений. Редиректы не выполняются,
+// чтобы уведомление не ушло на адрес, не прошедший проверку при подписке; адрес
+// при отправке проверяет клиент из httpclient.New.
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	var c http.Client
+	if client != nil {
+		c = *client
+	}
+	c.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &WebhookNotifier{client: &c}
+}
+
+// Notify отправляет уведомление; ответ с кодом 3xx и выше считается ошибкой доставки
+func (n *WebhookNotifier) Notify(ctx context.Context, subscription *entity.HealthSubscription, alert entity.HealthAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Shortener-Event", alert.Event)
+	if subscription.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(subscription.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Shortener-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyRead))
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/service"
+	"github.com/oziev02/Shortener/internal/infrastructure/httpclient"
+)
+
+func TestWebhookNotifierSignsBody(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	alert := entity.HealthAlert{
+		Event:       entity.HealthEventBroken,
+		ShortURL:    "abc123",
+		OriginalURL: "https://example.com",
+		Health:      entity.LinkHealth{Status: entity.HealthBroken, StatusCode: http.StatusNotFound},
+	}
+	subscription := &entity.HealthSubscription{WebhookURL: server.URL, Secret: "s3cret"}
+
+	if err := NewWebhookNotifier(server.Client()).Notify(context.Background(), subscription, alert); err != nil {
+		t.Fatalf("Notify = %v", err)
+	}
+
+	var got entity.HealthAlert
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("invalid body %q: %v", body, err)
+	}
+	if got.Event != alert.Event || got.ShortURL != alert.ShortURL || got.Health.Status != entity.HealthBroken {
+		t.Fatalf("body = %+v, want %+v", got, alert)
+	}
+	if header.Get("X-Shortener-Event") != entity.HealthEventBroken {
+		t.Fatalf("X-Shortener-Event = %q", header.Get("X-Shortener-Event"))
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); header.Get("X-Shortener-Signature") != want {
+		t.Fatalf("X-Shortener-Signature = %q, want %q", header.Get("X-Shortener-Signature"), want)
+	}
+}
+
+func TestWebhookNotifierWithoutSecret(t *testing.T) {
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get("X-Shortener-Signature")
+	}))
+	defer server.Close()
+
+	subscription := &entity.HealthSubscription{WebhookURL: server.URL}
+	if err := NewWebhookNotifier(server.Client()).Notify(context.Background(), subscription, entity.HealthAlert{}); err != nil {
+		t.Fatalf("Notify = %v", err)
+	}
+	if signature != "" {
+		t.Fatalf("X-Shortener-Signature = %q, want empty", signature)
+	}
+}
+
+func TestWebhookNotifierFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}},
+		{"redirect is not followed", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			subscription := &entity.HealthSubscription{WebhookURL: server.URL}
+			if err := NewWebhookNotifier(server.Client()).Notify(context.Background(), subscription, entity.HealthAlert{}); err == nil {
+				t.Fatal("Notify = nil, want delivery error")
+			}
+		})
+	}
+}
+
+func TestWebhookNotifierChecksAddressOnSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook reached loopback server")
+	}))
+	defer server.Close()
+
+	// Подписка прошла проверку раньше, но к моменту отправки адрес указывает на loopback
+	policy := service.NewURLPolicy(true, nil)
+	client := &http.Client{Transport: &http.Transport{DialContext: httpclient.NewDialer(policy, nil).DialContext}}
+
+	subscription := &entity.HealthSubscription{WebhookURL: server.URL}
+	err := NewWebhookNotifier(client).Notify(context.Background(), subscription, entity.HealthAlert{})
+	if !errors.Is(err, service.ErrPrivateTarget) {
+		t.Fatalf("Notify = %v, want ErrPrivateTarget", err)
+	}
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+)
+
+// DomainRules обрабатывает GET и POST /admin/rules
+func (h *Handler) DomainRules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		rules, err := h.domainRulesUseCase.List(r.Context())
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, rules)
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+		defer r.Body.Close()
+
+		var req usecase.CreateDomainRuleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+			return
+		}
+
+		rule, err := h.domainRulesUseCase.Create(r.Context(), req)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusCreated, rule)
+
+	default:
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+	}
+}
+
+// DomainRule обрабатывает DELETE /admin/rules/{id}
+func (h *Handler) DomainRule(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodDelete) {
+		return
+	}
+
+	id, ok := h.extractIDParam(r, "/admin/rules/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_rule_id", "Invalid rule ID", nil)
+		return
+	}
+
+	if err := h.domainRulesUseCase.Delete(r.Context(), id); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Blocked обрабатывает GET /blocked/{short_url} — страница-предупреждение о заблокированной ссылке
+func (h *Handler) Blocked(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	shortURL, ok := h.extractPathParam(r, "/blocked/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_short_url", "Invalid short URL", nil)
+		return
+	}
+
+	h.renderPage(w, http.StatusForbidden, "blocked.html", struct {
+		ShortCode string
+	}{ShortCode: shortURL})
+}
+
+// extractIDParam извлекает числовой идентификатор из пути URL
+func (h *Handler) extractIDParam(r *http.Request, prefix string) (int64, bool) {
+	value, ok := h.extractPathParam(r, prefix)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
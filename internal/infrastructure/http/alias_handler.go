@@ -0,0 +1,82 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// aliasCheckResponse ответ проверки алиаса с кодом причины недоступности
+type aliasCheckResponse struct {
+	*usecase.AliasCheckResponse
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// CheckAlias обрабатывает GET /aliases/check?alias=&limit= — проверку алиаса и подбор свободных вариантов
+func (h *Handler) CheckAlias(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_pagination", "limit must be an integer", err)
+			return
+		}
+	}
+
+	resp, err := h.shortenUseCase.CheckAlias(r.Context(), query.Get("alias"), limit)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	result := aliasCheckResponse{AliasCheckResponse: resp}
+	if resp.Reason != nil {
+		rejection, ok := classifyAliasError(resp.Reason)
+		if !ok {
+			rejection = aliasError{code: "invalid_alias", message: resp.Reason.Error()}
+		}
+		result.Reason = rejection.code
+		result.Message = rejection.message
+	}
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// aliasError ответ API на ошибку кастомного алиаса
+type aliasError struct {
+	status  int
+	code    string
+	message string
+}
+
+// classifyAliasError сопоставляет ошибку алиаса со статусом, кодом и сообщением ответа.
+// Используется и для ошибок создания ссылки, и для причины в проверке алиаса;
+// ok = false, если ошибка не относится к алиасу.
+func classifyAliasError(err error) (aliasError, bool) {
+	switch {
+	case errors.Is(err, usecase.ErrAliasExists):
+		return aliasError{http.StatusConflict, "alias_exists", err.Error()}, true
+	case errors.Is(err, service.ErrAliasTooShort):
+		return aliasError{http.StatusBadRequest, "alias_too_short", err.Error()}, true
+	case errors.Is(err, service.ErrAliasTooLong):
+		return aliasError{http.StatusBadRequest, "alias_too_long", err.Error()}, true
+	case errors.Is(err, service.ErrAliasInvalidCharacters):
+		return aliasError{http.StatusBadRequest, "alias_invalid_characters", err.Error()}, true
+	case errors.Is(err, service.ErrAliasReserved):
+		return aliasError{http.StatusBadRequest, "alias_reserved", err.Error()}, true
+	case errors.Is(err, service.ErrAliasProfane):
+		return aliasError{http.StatusBadRequest, "alias_profane", "Custom alias contains offensive language"}, true
+	case errors.Is(err, usecase.ErrInvalidAlias):
+		return aliasError{http.StatusBadRequest, "invalid_alias", err.Error()}, true
+	default:
+		return aliasError{}, false
+	}
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+func TestClassifyAliasError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{usecase.ErrAliasExists, http.StatusConflict, "alias_exists"},
+		{&usecase.AliasTakenError{Alias: "promo"}, http.StatusConflict, "alias_exists"},
+		{fmt.Errorf("%w: %w", usecase.ErrInvalidAlias, service.ErrAliasTooShort), http.StatusBadRequest, "alias_too_short"},
+		{fmt.Errorf("%w: %w", usecase.ErrInvalidAlias, service.ErrAliasTooLong), http.StatusBadRequest, "alias_too_long"},
+		{fmt.Errorf("%w: %w", usecase.ErrInvalidAlias, service.ErrAliasInvalidCharacters), http.StatusBadRequest, "alias_invalid_characters"},
+		{fmt.Errorf("%w: %w", usecase.ErrInvalidAlias, service.ErrAliasReserved), http.StatusBadRequest, "alias_reserved"},
+		{fmt.Errorf("%w: %w", usecase.ErrInvalidAlias, service.ErrAliasProfane), http.StatusBadRequest, "alias_profane"},
+		{usecase.ErrInvalidAlias, http.StatusBadRequest, "invalid_alias"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, ok := classifyAliasError(tt.err)
+			if !ok || got.status != tt.status || got.code != tt.code {
+				t.Fatalf("classifyAliasError(%v) = %+v, %v; want %d %s", tt.err, got, ok, tt.status, tt.code)
+			}
+		})
+	}
+
+	if _, ok := classifyAliasError(usecase.ErrLinkNotFound); ok {
+		t.Fatal("classifyAliasError(ErrLinkNotFound) = ok, want not an alias error")
+	}
+}
@@ -0,0 +1,239 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+)
+
+// Links обрабатывает GET /links?tag=&campaign=&limit=&offset=
+func (h *Handler) Links(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	req := usecase.ListLinksRequest{
+		Tag:      query.Get("tag"),
+		Campaign: query.Get("campaign"),
+	}
+
+	// Параметры страницы необязательны
+	var err error
+	if value := query.Get("limit"); value != "" {
+		if req.Limit, err = strconv.Atoi(value); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_pagination", "limit must be an integer", err)
+			return
+		}
+	}
+	if value := query.Get("offset"); value != "" {
+		if req.Offset, err = strconv.Atoi(value); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_pagination", "offset must be an integer", err)
+			return
+		}
+	}
+
+	resp, err := h.catalogUseCase.ListLinks(r.Context(), req)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// setLinkTags обрабатывает PUT /links/{short_url}/tags
+func (h *Handler) setLinkTags(w http.ResponseWriter, r *http.Request, shortURL string) {
+	if !h.ensureMethod(w, r, http.MethodPut) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+	defer r.Body.Close()
+
+	var req struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+		return
+	}
+
+	tags, err := h.catalogUseCase.SetLinkTags(r.Context(), shortURL, req.Tags)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"short_url": shortURL,
+		"tags":      tags,
+	})
+}
+
+// setLinkCampaign обрабатывает PUT /links/{short_url}/campaign
+func (h *Handler) setLinkCampaign(w http.ResponseWriter, r *http.Request, shortURL string) {
+	if !h.ensureMethod(w, r, http.MethodPut) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+	defer r.Body.Close()
+
+	var req struct {
+		Campaign string `json:"campaign"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+		return
+	}
+
+	if err := h.catalogUseCase.SetLinkCampaign(r.Context(), shortURL, req.Campaign); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Campaigns обрабатывает GET и POST /campaigns
+func (h *Handler) Campaigns(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		campaigns, err := h.catalogUseCase.ListCampaigns(r.Context())
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, campaigns)
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+		defer r.Body.Close()
+
+		var req usecase.CreateCampaignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+			return
+		}
+
+		campaign, err := h.catalogUseCase.CreateCampaign(r.Context(), req)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusCreated, campaign)
+
+	default:
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+	}
+}
+
+// Campaign обрабатывает GET, PATCH и DELETE /campaigns/{name} и GET /campaigns/{name}/analytics
+func (h *Handler) Campaign(w http.ResponseWriter, r *http.Request) {
+	name, ok := h.extractPathParam(r, "/campaigns/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_campaign", "Invalid campaign name", nil)
+		return
+	}
+
+	if campaignName, action, found := strings.Cut(name, "/"); found {
+		if action != "analytics" {
+			h.respondError(w, http.StatusNotFound, "not_found", "Not found", nil)
+			return
+		}
+		h.campaignAnalytics(w, r, campaignName)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		campaign, err := h.catalogUseCase.GetCampaign(r.Context(), name)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, campaign)
+
+	case http.MethodPatch:
+		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+		defer r.Body.Close()
+
+		var req usecase.UpdateCampaignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+			return
+		}
+		req.Name = name
+
+		campaign, err := h.catalogUseCase.UpdateCampaign(r.Context(), req)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, campaign)
+
+	case http.MethodDelete:
+		if err := h.catalogUseCase.DeleteCampaign(r.Context(), name); err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+	}
+}
+
+// campaignAnalytics обрабатывает GET /campaigns/{name}/analytics
+func (h *Handler) campaignAnalytics(w http.ResponseWriter, r *http.Request, name string) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	analytics, err := h.catalogUseCase.CampaignAnalytics(r.Context(), name)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, analytics)
+}
+
+// Tags обрабатывает GET /tags
+func (h *Handler) Tags(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	tags, err := h.catalogUseCase.ListTags(r.Context())
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, tags)
+}
+
+// Tag обрабатывает DELETE /tags/{name}
+func (h *Handler) Tag(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodDelete) {
+		return
+	}
+
+	name, ok := h.extractPathParam(r, "/tags/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_tag", "Invalid tag name", nil)
+		return
+	}
+
+	if err := h.catalogUseCase.DeleteTag(r.Context(), name); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
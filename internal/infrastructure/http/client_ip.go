@@ -0,0 +1,162 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Заголовки, в которые доверенный прокси записывает адрес клиента
+const (
+	ProxyHeaderXForwardedFor = "x-forwarded-for"
+	ProxyHeaderForwarded     = "forwarded"
+	ProxyHeaderXRealIP       = "x-real-ip"
+)
+
+// ClientIPResolver определяет IP-адрес клиента с учётом доверенных прокси.
+// Учитывается только заголовок, который пишет доверенный прокси, и только если запрос
+// пришёл от доверенного прокси: остальные заголовки мог прислать сам клиент.
+// Цепочка адресов просматривается справа налево, доверенные прокси пропускаются,
+// первый недоверенный адрес считается адресом клиента.
+type ClientIPResolver struct {
+	trusted []*net.IPNet
+	header  string
+}
+
+// NewClientIPResolver создаёт резолвер по списку CIDR доверенных прокси и имени заголовка,
+// который они заполняют (ProxyHeaderXForwardedFor, ProxyHeaderForwarded или ProxyHeaderXRealIP).
+// Одиночный IP-адрес трактуется как сеть из одного адреса.
+func NewClientIPResolver(trustedProxies []string, header string) (*ClientIPResolver, error) {
+	header = strings.ToLower(strings.TrimSpace(header))
+	switch header {
+	case ProxyHeaderXForwardedFor, ProxyHeaderForwarded, ProxyHeaderXRealIP:
+	default:
+		return nil, fmt.Errorf("unsupported trusted proxy header %q", header)
+	}
+
+	resolver := &ClientIPResolver{header: header}
+	for _, cidr := range trustedProxies {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", cidr)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			resolver.trusted = append(resolver.trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+		}
+		resolver.trusted = append(resolver.trusted, network)
+	}
+	return resolver, nil
+}
+
+// ClientIP возвращает IP-адрес клиента
+func (c *ClientIPResolver) ClientIP(r *http.Request) string {
+	remote := parseHostIP(r.RemoteAddr)
+	if remote == nil {
+		return r.RemoteAddr
+	}
+	if !c.isTrusted(remote) {
+		return remote.String()
+	}
+
+	chain := c.forwardedChain(r.Header)
+	if len(chain) == 0 {
+		return remote.String()
+	}
+
+	// Идём справа налево: правые записи добавлены нашими прокси, левые мог подделать клиент
+	nearest := remote
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := parseHostIP(chain[i])
+		if ip == nil {
+			// Нечитаемая запись (например, "unknown"): дальше цепочке доверять нельзя
+			return nearest.String()
+		}
+		if !c.isTrusted(ip) {
+			return ip.String()
+		}
+		nearest = ip
+	}
+
+	// Все адреса в цепочке доверенные: клиентом считается самый левый
+	return nearest.String()
+}
+
+// forwardedChain возвращает цепочку адресов из заголовка доверенного прокси
+func (c *ClientIPResolver) forwardedChain(header http.Header) []string {
+	switch c.header {
+	case ProxyHeaderForwarded:
+		return forwardedFor(header.Values("Forwarded"))
+	case ProxyHeaderXRealIP:
+		if value := strings.TrimSpace(header.Get("X-Real-IP")); value != "" {
+			return []string{value}
+		}
+		return nil
+	default:
+		return splitHeaderList(header.Values("X-Forwarded-For"))
+	}
+}
+
+// isTrusted проверяет, входит ли адрес в доверенные сети
+func (c *ClientIPResolver) isTrusted(ip net.IP) bool {
+	for _, network := range c.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHostIP разбирает адрес вида "ip", "ip:port", "[ipv6]" или "[ipv6]:port"
+func parseHostIP(value string) net.IP {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	return net.ParseIP(strings.Trim(value, "[]"))
+}
+
+// splitHeaderList разбирает заголовки со списком значений через запятую
+func splitHeaderList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+// forwardedFor извлекает параметры for= из заголовков Forwarded (RFC 7239):
+// Forwarded: for=192.0.2.60;proto=http, for="[2001:db8:cafe::17]:4711"
+func forwardedFor(values []string) []string {
+	var result []string
+	for _, element := range splitHeaderList(values) {
+		for _, pair := range strings.Split(element, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || !strings.EqualFold(name, "for") {
+				continue
+			}
+			result = append(result, strings.Trim(value, `"`))
+		}
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{
+			name:    "untrusted peer ignores headers",
+			header:  ProxyHeaderXForwardedFor,
+			remote:  "203.0.113.7:4000",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:    "203.0.113.7",
+		},
+		{
+			name:   "spoofed forwarded next to real xff chain",
+			header: ProxyHeaderXForwardedFor,
+			remote: "10.0.0.2:4000",
+			headers: map[string]string{
+				"Forwarded":       "for=1.2.3.4",
+				"X-Forwarded-For": "6.6.6.6, 198.51.100.9, 10.0.0.5",
+			},
+			want: "198.51.100.9",
+		},
+		{
+			name:   "spoofed xff when proxy writes forwarded",
+			header: ProxyHeaderForwarded,
+			remote: "10.0.0.2:4000",
+			headers: map[string]string{
+				"Forwarded":       `for=6.6.6.6, for="198.51.100.9:4711";proto=https`,
+				"X-Forwarded-For": "1.2.3.4",
+			},
+			want: "198.51.100.9",
+		},
+		{
+			name:    "forwarded without the configured header",
+			header:  ProxyHeaderXForwardedFor,
+			remote:  "10.0.0.2:4000",
+			headers: map[string]string{"Forwarded": "for=1.2.3.4"},
+			want:    "10.0.0.2",
+		},
+		{
+			name:    "x-real-ip",
+			header:  ProxyHeaderXRealIP,
+			remote:  "10.0.0.2:4000",
+			headers: map[string]string{"X-Real-IP": "198.51.100.9", "X-Forwarded-For": "1.2.3.4"},
+			want:    "198.51.100.9",
+		},
+		{
+			name:    "unparsable entry stops at nearest proxy",
+			header:  ProxyHeaderXForwardedFor,
+			remote:  "10.0.0.2:4000",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.9, unknown"},
+			want:    "10.0.0.2",
+		},
+		{
+			name:    "ipv6 proxy",
+			header:  ProxyHeaderXForwardedFor,
+			remote:  "[::1]:4000",
+			headers: map[string]string{"X-Forwarded-For": "2001:db8::1"},
+			want:    "2001:db8::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := NewClientIPResolver([]string{"10.0.0.0/8", "::1"}, tt.header)
+			if err != nil {
+				t.Fatalf("NewClientIPResolver = %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			if got := resolver.ClientIP(req); got != tt.want {
+				t.Fatalf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientIPResolverRejectsUnknownHeader(t *testing.T) {
+	if _, err := NewClientIPResolver(nil, "x-client-ip"); err == nil {
+		t.Fatal("NewClientIPResolver = nil error, want unsupported header")
+	}
+	if _, err := NewClientIPResolver(nil, "X-Forwarded-For"); err != nil {
+		t.Fatalf("NewClientIPResolver = %v, want header names to be case-insensitive", err)
+	}
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+)
+
+// Convert обрабатывает POST /convert/{short_url} — регистрацию конверсии для A/B-теста.
+// GET поддерживается для подключения в виде пикселя.
+func (h *Handler) Convert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+		return
+	}
+
+	shortURL, ok := h.extractPathParam(r, "/convert/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_short_url", "Invalid short URL", nil)
+		return
+	}
+
+	visitorID, _ := h.visitorID(r)
+	if _, err := h.conversionUseCase.Execute(r.Context(), usecase.ConversionRequest{
+		ShortURL:  shortURL,
+		VisitorID: visitorID,
+	}); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusNoContent)
+}
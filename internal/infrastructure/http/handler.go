@@ -5,16 +5,27 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/oziev02/Shortener/internal/application/usecase"
+	"github.com/oziev02/Shortener/internal/domain/service"
 )
 
 const (
 	// MaxURLLength максимальная длина URL
-	MaxURLLength = 2048
+	MaxURLLength = usecase.MaxURLLength
 	// MaxRequestBodySize максимальный размер тела запроса (1MB)
 	MaxRequestBodySize = 1024 * 1024
+	// MaxImportBodySize максимальный размер файла импорта (32MB)
+	MaxImportBodySize = 32 * 1024 * 1024
+	// MaxPasswordFormSize максимальный размер формы ввода пароля
+	MaxPasswordFormSize = 4 * 1024
+	// MaxIdempotencyKeyLength максимальная длина заголовка Idempotency-Key
+	MaxIdempotencyKeyLength = usecase.MaxIdempotencyKeyLength
+	// DeepLinkFallbackDelay время ожидания открытия приложения перед переходом на запасной адрес
+	DeepLinkFallbackDelay = 1500 * time.Millisecond
 )
 
 // ErrorResponse структурированный ответ об ошибке
@@ -22,14 +33,31 @@ type ErrorResponse struct {
 	Error   string `json:"error"`
 	Code    string `json:"code,omitempty"`
 	Message string `json:"message,omitempty"`
+	// Suggestions свободные варианты вместо занятого алиаса
+	Suggestions []string `json:"suggestions,omitempty"`
 }
 
 // Handler обрабатывает HTTP запросы
 type Handler struct {
-	shortenUseCase   *usecase.ShortenUseCase
-	redirectUseCase  *usecase.RedirectUseCase
-	analyticsUseCase *usecase.AnalyticsUseCase
-	logger           Logger
+	shortenUseCase     *usecase.ShortenUseCase
+	redirectUseCase    *usecase.RedirectUseCase
+	analyticsUseCase   *usecase.AnalyticsUseCase
+	importUseCase      *usecase.ImportUseCase
+	exportUseCase      *usecase.ExportUseCase
+	domainRulesUseCase *usecase.DomainRulesUseCase
+	signUseCase        *usecase.SignUseCase
+	qrUseCase          *usecase.QRUseCase
+	conversionUseCase  *usecase.ConversionUseCase
+	linkEditUseCase    *usecase.LinkEditUseCase
+	catalogUseCase     *usecase.CatalogUseCase
+	utmUseCase         *usecase.UTMUseCase
+	pageUseCase        *usecase.PageUseCase
+	healthUseCase      *usecase.HealthUseCase
+	openGraphUseCase   *usecase.OpenGraphUseCase
+	clientIPResolver   *ClientIPResolver
+	linkUnlocker       *LinkUnlocker
+	wellKnownFiles     *WellKnownFiles
+	logger             Logger
 }
 
 // NewHandler создаёт новый HTTP handler
@@ -37,16 +65,46 @@ func NewHandler(
 	shortenUseCase *usecase.ShortenUseCase,
 	redirectUseCase *usecase.RedirectUseCase,
 	analyticsUseCase *usecase.AnalyticsUseCase,
+	importUseCase *usecase.ImportUseCase,
+	exportUseCase *usecase.ExportUseCase,
+	domainRulesUseCase *usecase.DomainRulesUseCase,
+	signUseCase *usecase.SignUseCase,
+	qrUseCase *usecase.QRUseCase,
+	conversionUseCase *usecase.ConversionUseCase,
+	linkEditUseCase *usecase.LinkEditUseCase,
+	catalogUseCase *usecase.CatalogUseCase,
+	utmUseCase *usecase.UTMUseCase,
+	pageUseCase *usecase.PageUseCase,
+	healthUseCase *usecase.HealthUseCase,
+	openGraphUseCase *usecase.OpenGraphUseCase,
+	clientIPResolver *ClientIPResolver,
+	linkUnlocker *LinkUnlocker,
+	wellKnownFiles *WellKnownFiles,
 	logger Logger,
 ) *Handler {
 	if logger == nil {
 		logger = &NoOpLogger{}
 	}
 	return &Handler{
-		shortenUseCase:   shortenUseCase,
-		redirectUseCase:  redirectUseCase,
-		analyticsUseCase: analyticsUseCase,
-		logger:           logger,
+		shortenUseCase:     shortenUseCase,
+		redirectUseCase:    redirectUseCase,
+		analyticsUseCase:   analyticsUseCase,
+		importUseCase:      importUseCase,
+		exportUseCase:      exportUseCase,
+		domainRulesUseCase: domainRulesUseCase,
+		signUseCase:        signUseCase,
+		qrUseCase:          qrUseCase,
+		conversionUseCase:  conversionUseCase,
+		linkEditUseCase:    linkEditUseCase,
+		catalogUseCase:     catalogUseCase,
+		utmUseCase:         utmUseCase,
+		pageUseCase:        pageUseCase,
+		healthUseCase:      healthUseCase,
+		openGraphUseCase:   openGraphUseCase,
+		clientIPResolver:   clientIPResolver,
+		linkUnlocker:       linkUnlocker,
+		wellKnownFiles:     wellKnownFiles,
+		logger:             logger,
 	}
 }
 
@@ -77,18 +135,40 @@ func (h *Handler) Shorten(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.shortenUseCase.Execute(r.Context(), req)
+	var resp *usecase.CreateLinkResponse
+	var err error
+
+	// Повторные запросы с тем же Idempotency-Key получают сохранённый ответ
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		if len(key) > MaxIdempotencyKeyLength {
+			h.respondError(w, http.StatusBadRequest, "invalid_idempotency_key", "Idempotency-Key is too long", nil)
+			return
+		}
+
+		var replayed bool
+		resp, replayed, err = h.shortenUseCase.ExecuteIdempotent(r.Context(), key, req)
+		if replayed {
+			w.Header().Set("Idempotent-Replayed", "true")
+		}
+	} else {
+		resp, err = h.shortenUseCase.Execute(r.Context(), req)
+	}
 	if err != nil {
 		h.handleUseCaseError(w, err)
 		return
 	}
 
-	h.respondJSON(w, http.StatusCreated, resp)
+	statusCode := http.StatusCreated
+	if resp.Reused {
+		statusCode = http.StatusOK
+	}
+	h.respondJSON(w, statusCode, resp)
 }
 
-// Redirect обрабатывает GET /s/{short_url}
+// Redirect обрабатывает GET /s/{short_url}; POST используется для ввода пароля защищённой ссылки
 func (h *Handler) Redirect(w http.ResponseWriter, r *http.Request) {
-	if !h.ensureMethod(w, r, http.MethodGet) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
 		return
 	}
 
@@ -98,16 +178,148 @@ func (h *Handler) Redirect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userAgent := r.Header.Get("User-Agent")
-	ipAddress := getIPAddress(r)
+	// Суффикс "+" или параметр preview открывают страницу предпросмотра
+	query := r.URL.Query()
+	shortURL, preview := strings.CutSuffix(shortURL, "+")
+	if _, ok := query["preview"]; ok {
+		preview = true
+	}
+	if shortURL == "" {
+		h.respondError(w, http.StatusBadRequest, "invalid_short_url", "Invalid short URL", nil)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		h.unlockLink(w, r, shortURL)
+		return
+	}
+
+	visitorID, hasVisitorCookie := h.visitorID(r)
+	req := usecase.RedirectRequest{
+		ShortURL:        shortURL,
+		VisitorID:       visitorID,
+		UserAgent:       r.Header.Get("User-Agent"),
+		IPAddress:       h.clientIPResolver.ClientIP(r),
+		Headers:         r.Header,
+		Expires:         query.Get("exp"),
+		Signature:       query.Get("sig"),
+		UnlockedVersion: h.linkUnlocker.UnlockedVersion(r, shortURL),
+	}
+
+	if preview {
+		link, err := h.redirectUseCase.Preview(r.Context(), req)
+		if err != nil {
+			h.handleRedirectError(w, r, shortURL, err)
+			return
+		}
+		// До запуска адрес назначения не раскрывается
+		if link.IsPending(time.Now()) {
+			h.renderComingSoonPage(w, link)
+			return
+		}
+		h.renderPreviewPage(w, r, link)
+		return
+	}
+
+	// Боты соцсетей получают страницу с превью, люди — обычный редирект
+	if service.IsPreviewBot(req.UserAgent) && h.renderSocialPreview(w, r, req) {
+		return
+	}
+
+	h.followLink(w, r, req, hasVisitorCookie)
+}
+
+// followLink выполняет переход по ссылке и отвечает редиректом или HTML-страницей
+func (h *Handler) followLink(w http.ResponseWriter, r *http.Request, req usecase.RedirectRequest, hasVisitorCookie bool) {
+	result, err := h.redirectUseCase.Execute(r.Context(), req)
+	if err != nil {
+		h.handleRedirectError(w, r, req.ShortURL, err)
+		return
+	}
+
+	if result.Pending {
+		if result.URL == "" {
+			h.renderComingSoonPage(w, result.Link)
+			return
+		}
+		// Адрес до запуска меняется на основной в момент открытия окна, поэтому не кэшируется
+		w.Header().Set("Cache-Control", "no-store")
+		http.Redirect(w, r, result.URL, http.StatusFound)
+		return
+	}
+
+	// Закрепляем вариант A/B-теста за посетителем
+	if result.Variant != "" && !hasVisitorCookie {
+		h.setVisitorCookie(w, r, req.VisitorID)
+	}
+
+	// Мобильные клиенты сначала пробуют открыть приложение
+	if result.DeepLink != nil {
+		h.renderDeepLinkPage(w, result.DeepLink)
+		return
+	}
+
+	if result.Link.InterstitialSeconds > 0 {
+		h.renderInterstitialPage(w, result)
+		return
+	}
+
+	http.Redirect(w, r, result.URL, http.StatusFound)
+}
+
+// handleRedirectError обрабатывает ошибки перехода: заблокированные и защищённые
+// ссылки получают HTML-страницы, остальные ошибки — стандартный JSON-ответ
+func (h *Handler) handleRedirectError(w http.ResponseWriter, r *http.Request, shortURL string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrLinkBlocked):
+		http.Redirect(w, r, "/blocked/"+url.PathEscape(shortURL), http.StatusFound)
+	case errors.Is(err, usecase.ErrPasswordRequired):
+		h.renderPasswordPage(w, http.StatusUnauthorized, shortURL, "")
+	default:
+		h.handleUseCaseError(w, err)
+	}
+}
+
+// unlockLink проверяет пароль из формы и выдаёт cookie, после чего повторяет переход
+func (h *Handler) unlockLink(w http.ResponseWriter, r *http.Request, shortURL string) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxPasswordFormSize)
+	defer r.Body.Close()
+
+	// Ограничиваем перебор паролей
+	result, err := h.linkUnlocker.AllowAttempt(r.Context(), h.clientIPResolver.ClientIP(r), shortURL)
+	if err != nil {
+		h.logger.Error("password rate limiter failed", err)
+	} else if !result.Allowed {
+		retryAfter := ceilSeconds(result.RetryAfter)
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		h.renderPasswordPage(w, http.StatusTooManyRequests, shortURL, "Слишком много попыток. Попробуйте позже.")
+		return
+	}
 
-	originalURL, err := h.redirectUseCase.Execute(r.Context(), shortURL, userAgent, ipAddress)
+	version, err := h.redirectUseCase.Unlock(r.Context(), shortURL, r.PostFormValue("password"))
+	if errors.Is(err, usecase.ErrWrongPassword) {
+		h.renderPasswordPage(w, http.StatusUnauthorized, shortURL, "Неверный пароль")
+		return
+	}
 	if err != nil {
 		h.handleUseCaseError(w, err)
 		return
 	}
 
-	http.Redirect(w, r, originalURL, http.StatusFound)
+	h.linkUnlocker.SetUnlocked(w, r, shortURL, version)
+	http.Redirect(w, r, r.URL.RequestURI(), http.StatusSeeOther)
+}
+
+// renderPasswordPage отрисовывает форму ввода пароля
+func (h *Handler) renderPasswordPage(w http.ResponseWriter, statusCode int, shortURL, message string) {
+	w.Header().Set("Cache-Control", "no-store")
+	h.renderPage(w, statusCode, "password.html", struct {
+		ShortCode string
+		Error     string
+	}{ShortCode: shortURL, Error: message})
 }
 
 // Analytics обрабатывает GET /analytics/{short_url}
@@ -169,15 +381,123 @@ func (h *Handler) extractPathParam(r *http.Request, prefix string) (string, bool
 func (h *Handler) handleUseCaseError(w http.ResponseWriter, err error) {
 	h.logger.Error("usecase error", err)
 
+	var aliasTaken *usecase.AliasTakenError
+	if errors.As(err, &aliasTaken) {
+		h.respondJSON(w, http.StatusConflict, ErrorResponse{
+			Error:       err.Error(),
+			Code:        "alias_exists",
+			Message:     err.Error(),
+			Suggestions: aliasTaken.Suggestions,
+		})
+		return
+	}
+
+	if rejection, ok := classifyAliasError(err); ok {
+		h.respondError(w, rejection.status, rejection.code, rejection.message, err)
+		return
+	}
+
 	switch {
-	case errors.Is(err, usecase.ErrAliasExists):
-		h.respondError(w, http.StatusConflict, "alias_exists", err.Error(), err)
 	case errors.Is(err, usecase.ErrLinkNotFound):
 		h.respondError(w, http.StatusNotFound, "link_not_found", "Link not found", err)
 	case errors.Is(err, usecase.ErrInvalidURL):
 		h.respondError(w, http.StatusBadRequest, "invalid_url", err.Error(), err)
 	case errors.Is(err, usecase.ErrURLRequired):
 		h.respondError(w, http.StatusBadRequest, "url_required", err.Error(), err)
+	case errors.Is(err, usecase.ErrTargetNotAllowed):
+		h.respondError(w, http.StatusBadRequest, "target_not_allowed", "Target URL points to a private or local network", err)
+	case errors.Is(err, usecase.ErrDomainBlocked):
+		h.respondError(w, http.StatusForbidden, "domain_blocked", "Target domain is blocked", err)
+	case errors.Is(err, usecase.ErrLinkBlocked):
+		h.respondError(w, http.StatusForbidden, "link_blocked", "Link target is blocked", err)
+	case errors.Is(err, usecase.ErrInvalidDomainRule):
+		h.respondError(w, http.StatusBadRequest, "invalid_domain_rule", err.Error(), err)
+	case errors.Is(err, usecase.ErrDomainRuleNotFound):
+		h.respondError(w, http.StatusNotFound, "domain_rule_not_found", "Domain rule not found", err)
+	case errors.Is(err, usecase.ErrInvalidLinkPassword):
+		h.respondError(w, http.StatusBadRequest, "invalid_password", err.Error(), err)
+	case errors.Is(err, usecase.ErrPasswordRequired):
+		h.respondError(w, http.StatusUnauthorized, "password_required", "Link is password protected", err)
+	case errors.Is(err, usecase.ErrWrongPassword):
+		h.respondError(w, http.StatusUnauthorized, "wrong_password", "Wrong link password", err)
+	case errors.Is(err, usecase.ErrSignatureRequired):
+		h.respondError(w, http.StatusForbidden, "signature_required", "Link requires a signed URL", err)
+	case errors.Is(err, usecase.ErrInvalidSignature):
+		h.respondError(w, http.StatusForbidden, "invalid_signature", "Invalid URL signature", err)
+	case errors.Is(err, usecase.ErrSignatureExpired):
+		h.respondError(w, http.StatusGone, "signature_expired", "Signed URL has expired", err)
+	case errors.Is(err, usecase.ErrSigningDisabled):
+		h.respondError(w, http.StatusServiceUnavailable, "signing_disabled", "URL signing is not configured", err)
+	case errors.Is(err, usecase.ErrInvalidExpiry):
+		h.respondError(w, http.StatusBadRequest, "invalid_expiry", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidInterstitial):
+		h.respondError(w, http.StatusBadRequest, "invalid_interstitial", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidQROptions):
+		h.respondError(w, http.StatusBadRequest, "invalid_qr_options", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidTargetingRule):
+		h.respondError(w, http.StatusBadRequest, "invalid_targeting_rule", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidGeoRule):
+		h.respondError(w, http.StatusBadRequest, "invalid_geo_rule", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidRoutingRule):
+		h.respondError(w, http.StatusBadRequest, "invalid_routing_rule", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidActiveWindow):
+		h.respondError(w, http.StatusBadRequest, "invalid_active_window", err.Error(), err)
+	case errors.Is(err, usecase.ErrLinkExpired):
+		h.respondError(w, http.StatusGone, "link_expired", "Link has expired", err)
+	case errors.Is(err, usecase.ErrInvalidVariant):
+		h.respondError(w, http.StatusBadRequest, "invalid_variant", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidLinkUpdate):
+		h.respondError(w, http.StatusBadRequest, "invalid_link_update", err.Error(), err)
+	case errors.Is(err, usecase.ErrRevisionConflict):
+		h.respondError(w, http.StatusPreconditionFailed, "revision_conflict", err.Error(), err)
+	case errors.Is(err, usecase.ErrRevisionNotFound):
+		h.respondError(w, http.StatusNotFound, "revision_not_found", "Revision not found", err)
+	case errors.Is(err, usecase.ErrInvalidTag):
+		h.respondError(w, http.StatusBadRequest, "invalid_tag", err.Error(), err)
+	case errors.Is(err, usecase.ErrTagNotFound):
+		h.respondError(w, http.StatusNotFound, "tag_not_found", "Tag not found", err)
+	case errors.Is(err, usecase.ErrInvalidCampaign):
+		h.respondError(w, http.StatusBadRequest, "invalid_campaign", err.Error(), err)
+	case errors.Is(err, usecase.ErrCampaignNotFound):
+		h.respondError(w, http.StatusNotFound, "campaign_not_found", "Campaign not found", err)
+	case errors.Is(err, usecase.ErrCampaignExists):
+		h.respondError(w, http.StatusConflict, "campaign_exists", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidUTM):
+		h.respondError(w, http.StatusBadRequest, "invalid_utm", err.Error(), err)
+	case errors.Is(err, usecase.ErrUTMTemplateNotFound):
+		h.respondError(w, http.StatusNotFound, "utm_template_not_found", "UTM template not found", err)
+	case errors.Is(err, usecase.ErrUTMTemplateExists):
+		h.respondError(w, http.StatusConflict, "utm_template_exists", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidPage):
+		h.respondError(w, http.StatusBadRequest, "invalid_page", err.Error(), err)
+	case errors.Is(err, usecase.ErrPageNotFound):
+		h.respondError(w, http.StatusNotFound, "page_not_found", "Page not found", err)
+	case errors.Is(err, usecase.ErrPageExists):
+		h.respondError(w, http.StatusConflict, "page_exists", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidDeepLink):
+		h.respondError(w, http.StatusBadRequest, "invalid_deep_link", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidOpenGraph):
+		h.respondError(w, http.StatusBadRequest, "invalid_open_graph", err.Error(), err)
+	case errors.Is(err, usecase.ErrOpenGraphFetchDisabled):
+		h.respondError(w, http.StatusServiceUnavailable, "open_graph_fetch_disabled", "Open Graph fetching is disabled", err)
+	case errors.Is(err, usecase.ErrOpenGraphFetchFailed):
+		h.respondError(w, http.StatusBadGateway, "open_graph_fetch_failed", err.Error(), err)
+	case errors.Is(err, usecase.ErrInvalidHealthSubscription):
+		h.respondError(w, http.StatusBadRequest, "invalid_health_subscription", err.Error(), err)
+	case errors.Is(err, usecase.ErrHealthSubscriptionNotFound):
+		h.respondError(w, http.StatusNotFound, "health_subscription_not_found", "Health subscription not found", err)
+	case errors.Is(err, usecase.ErrHealthCheckDisabled):
+		h.respondError(w, http.StatusServiceUnavailable, "health_check_disabled", "Health checks are disabled", err)
+	case errors.Is(err, usecase.ErrUnsupportedFormat):
+		h.respondError(w, http.StatusBadRequest, "unsupported_format", "Format must be csv or json", err)
+	case errors.Is(err, usecase.ErrInvalidImportFile):
+		h.respondError(w, http.StatusBadRequest, "invalid_import_file", err.Error(), err)
+	case errors.Is(err, usecase.ErrIdempotencyKeyReused):
+		h.respondError(w, http.StatusUnprocessableEntity, "idempotency_key_reused", err.Error(), err)
+	case errors.Is(err, usecase.ErrIdempotencyInProgress):
+		h.respondError(w, http.StatusConflict, "idempotency_in_progress", err.Error(), err)
+	case errors.Is(err, usecase.ErrImportJobNotFound):
+		h.respondError(w, http.StatusNotFound, "import_job_not_found", "Import job not found", err)
 	default:
 		h.respondError(w, http.StatusInternalServerError, "internal_error", "Internal server error", err)
 	}
@@ -213,50 +533,5 @@ func (h *Handler) respondJSON(w http.ResponseWriter, statusCode int, data interf
 
 // validateURL проверяет валидность URL
 func validateURL(urlStr string) error {
-	if len(urlStr) > MaxURLLength {
-		return errors.New("URL too long")
-	}
-
-	parsedURL, err := url.Parse(urlStr)
-	if err != nil {
-		return err
-	}
-
-	if parsedURL.Scheme == "" {
-		return errors.New("URL scheme is required")
-	}
-
-	if parsedURL.Host == "" {
-		return errors.New("URL host is required")
-	}
-
-	// Проверяем что схема http или https
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return errors.New("URL scheme must be http or https")
-	}
-
-	return nil
-}
-
-// getIPAddress извлекает IP адрес из запроса
-func getIPAddress(r *http.Request) string {
-	// Проверяем заголовок X-Forwarded-For (для прокси)
-	forwarded := r.Header.Get("X-Forwarded-For")
-	if forwarded != "" {
-		ips := strings.Split(forwarded, ",")
-		return strings.TrimSpace(ips[0])
-	}
-
-	// Проверяем заголовок X-Real-IP
-	realIP := r.Header.Get("X-Real-IP")
-	if realIP != "" {
-		return realIP
-	}
-
-	// Используем RemoteAddr
-	ip := r.RemoteAddr
-	if idx := strings.LastIndex(ip, ":"); idx != -1 {
-		ip = ip[:idx]
-	}
-	return ip
+	return usecase.ValidateURL(urlStr)
 }
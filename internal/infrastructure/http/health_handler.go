@@ -0,0 +1,85 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+)
+
+// linkHealth обрабатывает GET и POST /links/{short_url}/health: GET возвращает состояние
+// доступности и последние проверки, POST проверяет адрес немедленно
+func (h *Handler) linkHealth(w http.ResponseWriter, r *http.Request, shortURL string) {
+	var (
+		report *usecase.LinkHealthReport
+		err    error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		report, err = h.healthUseCase.Health(r.Context(), shortURL)
+	case http.MethodPost:
+		report, err = h.healthUseCase.Check(r.Context(), shortURL)
+	default:
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+		return
+	}
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+// HealthSubscriptions обрабатывает GET и POST /health/subscriptions
+func (h *Handler) HealthSubscriptions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		subscriptions, err := h.healthUseCase.ListSubscriptions(r.Context())
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, subscriptions)
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+		defer r.Body.Close()
+
+		var req usecase.SubscribeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+			return
+		}
+
+		subscription, err := h.healthUseCase.Subscribe(r.Context(), req)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusCreated, subscription)
+
+	default:
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+	}
+}
+
+// HealthSubscription обрабатывает DELETE /health/subscriptions/{id}
+func (h *Handler) HealthSubscription(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodDelete) {
+		return
+	}
+
+	id, ok := h.extractIDParam(r, "/health/subscriptions/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_health_subscription", "Invalid subscription ID", nil)
+		return
+	}
+
+	if err := h.healthUseCase.Unsubscribe(r.Context(), id); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+)
+
+// Import обрабатывает POST /import
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	// Ограничиваем размер загружаемого файла
+	r.Body = http.MaxBytesReader(w, r.Body, MaxImportBodySize)
+	defer r.Body.Close()
+
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_request", "dry_run must be a boolean", err)
+			return
+		}
+		dryRun = parsed
+	}
+
+	format := importFormat(r)
+	records, err := h.importUseCase.Parse(format, r.Body)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	job, err := h.importUseCase.Start(format, records, dryRun)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/import/"+job.ID)
+	h.respondJSON(w, http.StatusAccepted, job)
+}
+
+// ImportStatus обрабатывает GET /import/{job_id}
+func (h *Handler) ImportStatus(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	jobID, ok := h.extractPathParam(r, "/import/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_job_id", "Invalid import job ID", nil)
+		return
+	}
+
+	job, err := h.importUseCase.GetJob(jobID)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, job)
+}
+
+// Export обрабатывает GET /export
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = usecase.FormatCSV
+	}
+
+	var contentType string
+	switch format {
+	case usecase.FormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	case usecase.FormatJSON:
+		contentType = "application/json"
+	default:
+		h.handleUseCaseError(w, usecase.ErrUnsupportedFormat)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="links.%s"`, format))
+
+	// Ответ уже начал отправляться, поэтому ошибку можно только залогировать
+	if err := h.exportUseCase.Execute(r.Context(), format, w); err != nil {
+		h.logger.Error("failed to export links", err, "format", format)
+	}
+}
+
+// importFormat определяет формат файла импорта по параметру запроса или Content-Type
+func importFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return usecase.FormatJSON
+	}
+	return usecase.FormatCSV
+}
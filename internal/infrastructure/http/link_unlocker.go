@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/infrastructure/ratelimit"
+)
+
+// unlockCookiePrefix префикс имени cookie подтверждённого пароля
+const unlockCookiePrefix = "slu_"
+
+// LinkUnlocker выдаёт и проверяет подписанные cookie для ссылок, защищённых паролем,
+// и ограничивает число попыток ввода пароля
+type LinkUnlocker struct {
+	secret  []byte
+	ttl     time.Duration
+	limiter ratelimit.Limiter
+	policy  ratelimit.Policy
+}
+
+// NewLinkUnlocker создаёт новый LinkUnlocker.
+// secret подписывает cookie, ttl задаёт время, в течение которого пароль не запрашивается повторно.
+// Если limiter равен nil или политика не задана, попытки ввода пароля не ограничиваются.
+func NewLinkUnlocker(secret []byte, ttl time.Duration, limiter ratelimit.Limiter, policy ratelimit.Policy) *LinkUnlocker {
+	return &LinkUnlocker{
+		secret:  secret,
+		ttl:     ttl,
+		limiter: limiter,
+		policy:  policy,
+	}
+}
+
+// UnlockedVersion возвращает версию ссылки из действующей cookie клиента
+// или пустую строку, если cookie нет, она истекла или подпись неверна
+func (u *LinkUnlocker) UnlockedVersion(r *http.Request, shortURL string) string {
+	cookie, err := r.Cookie(u.cookieName(shortURL))
+	if err != nil {
+		return ""
+	}
+
+	// Значение имеет вид exp.version.signature; версия сама может содержать точки
+	expires, rest, ok := strings.Cut(cookie.Value, ".")
+	i := strings.LastIndex(rest, ".")
+	if !ok || i < 0 {
+		return ""
+	}
+	version, signature := rest[:i], rest[i+1:]
+
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return ""
+	}
+
+	expected := u.sign(shortURL, exp, version)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ""
+	}
+	return version
+}
+
+// SetUnlocked выдаёт клиенту cookie, подтверждающую ввод пароля для версии ссылки
+func (u *LinkUnlocker) SetUnlocked(w http.ResponseWriter, r *http.Request, shortURL, version string) {
+	exp := time.Now().Add(u.ttl).Unix()
+	http.SetCookie(w, &http.Cookie{
+		Name:     u.cookieName(shortURL),
+		Value:    strconv.FormatInt(exp, 10) + "." + version + "." + u.sign(shortURL, exp, version),
+		Path:     "/s/",
+		MaxAge:   int(u.ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// AllowAttempt ограничивает число попыток ввода пароля для пары IP и ссылки
+func (u *LinkUnlocker) AllowAttempt(ctx context.Context, ipAddress, shortURL string) (ratelimit.Result, error) {
+	if u.limiter == nil || !u.policy.Enabled() {
+		return ratelimit.Result{Allowed: true}, nil
+	}
+	return u.limiter.Allow(ctx, ipAddress+":"+shortURL, u.policy)
+}
+
+// cookieName возвращает имя cookie для ссылки; код хэшируется, так как алиас
+// может содержать символы, недопустимые в имени cookie
+func (u *LinkUnlocker) cookieName(shortURL string) string {
+	sum := sha256.Sum256([]byte(shortURL))
+	return unlockCookiePrefix + hex.EncodeToString(sum[:8])
+}
+
+// sign вычисляет HMAC для кода ссылки, времени истечения и версии ссылки
+func (u *LinkUnlocker) sign(shortURL string, exp int64, version string) string {
+	mac := hmac.New(sha256.New, u.secret)
+	mac.Write([]byte(shortURL + "\n" + strconv.FormatInt(exp, 10) + "\n" + version))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/infrastructure/ratelimit"
+)
+
+func TestAllowAttempt(t *testing.T) {
+	policy := ratelimit.Policy{Name: "password", Limit: 1, Period: time.Minute, Burst: 1}
+	tests := []struct {
+		name     string
+		limiter  ratelimit.Limiter
+		policy   ratelimit.Policy
+		attempts int
+		allowed  int
+	}{
+		{"limited", ratelimit.NewMemoryLimiter(), policy, 3, 1},
+		{"rate limits disabled", nil, policy, 3, 3},
+		{"zero policy", ratelimit.NewMemoryLimiter(), ratelimit.Policy{Name: "password", Period: time.Minute}, 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unlocker := NewLinkUnlocker([]byte("secret"), time.Hour, tt.limiter, tt.policy)
+			allowed := 0
+			for i := 0; i < tt.attempts; i++ {
+				result, err := unlocker.AllowAttempt(context.Background(), "198.51.100.1", "abc123")
+				if err != nil {
+					t.Fatalf("AllowAttempt = %v", err)
+				}
+				if result.Allowed {
+					allowed++
+				}
+			}
+			if allowed != tt.allowed {
+				t.Fatalf("allowed %d of %d attempts, want %d", allowed, tt.attempts, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestUnlockedVersion(t *testing.T) {
+	unlocker := NewLinkUnlocker([]byte("secret"), time.Hour, nil, ratelimit.Policy{})
+
+	recorder := httptest.NewRecorder()
+	unlocker.SetUnlocked(recorder, httptest.NewRequest(http.MethodPost, "/s/abc123", nil), "abc123", "7.2")
+	cookie := recorder.Result().Cookies()[0]
+
+	request := func(value string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/s/abc123", nil)
+		req.AddCookie(&http.Cookie{Name: cookie.Name, Value: value})
+		return req
+	}
+
+	if got := unlocker.UnlockedVersion(request(cookie.Value), "abc123"); got != "7.2" {
+		t.Fatalf("UnlockedVersion = %q, want %q", got, "7.2")
+	}
+
+	exp, _, _ := strings.Cut(cookie.Value, ".")
+	signature := cookie.Value[strings.LastIndex(cookie.Value, ".")+1:]
+	tests := []struct {
+		name  string
+		value string
+	}{
+		// Подмена версии на текущую ревизию ссылки не проходит проверку подписи
+		{"forged version", exp + ".7.3." + signature},
+		{"expired", "1.7.2." + signature},
+		{"no version", exp + "." + signature},
+		{"garbage", "unlocked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unlocker.UnlockedVersion(request(tt.value), "abc123"); got != "" {
+				t.Fatalf("UnlockedVersion = %q, want empty", got)
+			}
+		})
+	}
+
+	// Cookie одной ссылки не подходит для другой
+	other := httptest.NewRequest(http.MethodGet, "/s/other", nil)
+	other.AddCookie(&http.Cookie{Name: unlocker.cookieName("other"), Value: cookie.Value})
+	if got := unlocker.UnlockedVersion(other, "other"); got != "" {
+		t.Fatalf("UnlockedVersion = %q for another link, want empty", got)
+	}
+}
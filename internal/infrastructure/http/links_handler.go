@@ -0,0 +1,187 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+)
+
+// LinkAction обрабатывает административные действия над ссылкой: PATCH /links/{short_url}
+// и /links/{short_url}/{action}
+func (h *Handler) LinkAction(w http.ResponseWriter, r *http.Request) {
+	path, ok := h.extractPathParam(r, "/links/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_short_url", "Invalid short URL", nil)
+		return
+	}
+
+	if r.Method == http.MethodPatch {
+		h.updateLink(w, r, path)
+		return
+	}
+
+	shortURL, action, ok := cutLastSegment(path)
+	if !ok {
+		h.respondError(w, http.StatusNotFound, "not_found", "Not found", nil)
+		return
+	}
+
+	switch action {
+	case "sign":
+		h.signLink(w, r, shortURL)
+	case "history":
+		h.linkHistory(w, r, shortURL)
+	case "rollback":
+		h.rollbackLink(w, r, shortURL)
+	case "tags":
+		h.setLinkTags(w, r, shortURL)
+	case "campaign":
+		h.setLinkCampaign(w, r, shortURL)
+	case "health":
+		h.linkHealth(w, r, shortURL)
+	case "open-graph":
+		h.linkOpenGraph(w, r, shortURL)
+	default:
+		h.respondError(w, http.StatusNotFound, "not_found", "Not found", nil)
+	}
+}
+
+// signLink обрабатывает POST /links/{short_url}/sign
+func (h *Handler) signLink(w http.ResponseWriter, r *http.Request, shortURL string) {
+	if !h.ensureMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+	defer r.Body.Close()
+
+	// Тело необязательно: без него используется срок действия по умолчанию
+	var req usecase.SignLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+		return
+	}
+	req.ShortURL = shortURL
+
+	resp, err := h.signUseCase.Execute(r.Context(), req)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// updateLink обрабатывает PATCH /links/{short_url}. Тело — JSON Merge Patch
+// к параметрам ссылки; If-Match с номером ревизии защищает от потери параллельных изменений.
+func (h *Handler) updateLink(w http.ResponseWriter, r *http.Request, shortURL string) {
+	expectedRevision, ok := parseRevisionETag(r.Header.Get("If-Match"))
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_if_match", "If-Match must be a revision ETag", nil)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+	defer r.Body.Close()
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+		return
+	}
+
+	revision, err := h.linkEditUseCase.Update(r.Context(), usecase.UpdateLinkRequest{
+		ShortURL:         shortURL,
+		Patch:            patch,
+		ExpectedRevision: expectedRevision,
+	})
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", revisionETag(revision.Revision))
+	h.respondJSON(w, http.StatusOK, revision)
+}
+
+// linkHistory обрабатывает GET /links/{short_url}/history
+func (h *Handler) linkHistory(w http.ResponseWriter, r *http.Request, shortURL string) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	history, err := h.linkEditUseCase.History(r.Context(), shortURL)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", revisionETag(history.Revision))
+	h.respondJSON(w, http.StatusOK, history)
+}
+
+// rollbackLink обрабатывает POST /links/{short_url}/rollback
+func (h *Handler) rollbackLink(w http.ResponseWriter, r *http.Request, shortURL string) {
+	if !h.ensureMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	expectedRevision, ok := parseRevisionETag(r.Header.Get("If-Match"))
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_if_match", "If-Match must be a revision ETag", nil)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+	defer r.Body.Close()
+
+	var req usecase.RollbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+		return
+	}
+	req.ShortURL = shortURL
+	req.ExpectedRevision = expectedRevision
+
+	revision, err := h.linkEditUseCase.Rollback(r.Context(), req)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", revisionETag(revision.Revision))
+	h.respondJSON(w, http.StatusOK, revision)
+}
+
+// revisionETag формирует ETag из номера ревизии ссылки
+func revisionETag(revision int) string {
+	return `"` + strconv.Itoa(revision) + `"`
+}
+
+// parseRevisionETag разбирает If-Match; пустое значение и "*" означают, что ревизия не проверяется
+func parseRevisionETag(header string) (int, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	value := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	revision, err := strconv.Atoi(value)
+	if err != nil || revision <= 0 {
+		return 0, false
+	}
+	return revision, true
+}
+
+// cutLastSegment отделяет последний сегмент пути; код ссылки может сам содержать "/"
+func cutLastSegment(path string) (string, string, bool) {
+	i := strings.LastIndex(path, "/")
+	if i <= 0 || i == len(path)-1 {
+		return "", "", false
+	}
+	return path[:i], path[i+1:], true
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"crypto/subtle"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/infrastructure/ratelimit"
+)
+
+// RateLimitPolicies политики ограничения частоты запросов для групп маршрутов
+type RateLimitPolicies struct {
+	Create    ratelimit.Policy
+	Analytics ratelimit.Policy
+	Redirect  ratelimit.Policy
+}
+
+// requireAdmin пропускает запрос только с токеном администратора в заголовке
+// Authorization: Bearer <token>. Если токен не настроен, административный API отключён.
+func (r *Router) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if r.adminToken == "" {
+			r.handler.respondError(w, http.StatusForbidden, "admin_disabled", "Admin API is disabled", nil)
+			return
+		}
+
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(r.adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			r.handler.respondError(w, http.StatusUnauthorized, "unauthorized", "Invalid admin token", nil)
+			return
+		}
+
+		next(w, req)
+	}
+}
+
+// rateLimit ограничивает частоту запросов по IP клиента.
+// Превышение лимита возвращает 429 с заголовками Retry-After и RateLimit-*.
+func (r *Router) rateLimit(policy ratelimit.Policy, next http.HandlerFunc) http.HandlerFunc {
+	if r.limiter == nil || !policy.Enabled() {
+		return next
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		result, err := r.limiter.Allow(req.Context(), r.rateLimitKey(req), policy)
+		if err != nil {
+			// Недоступность хранилища лимитов не должна останавливать сервис
+			r.handler.logger.Error("rate limiter failed", err, "policy", policy.Name)
+			next(w, req)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			r.handler.respondError(w, http.StatusTooManyRequests, "rate_limited", "Too many requests", nil)
+			return
+		}
+
+		next(w, req)
+	}
+}
+
+// rateLimitKey возвращает ключ клиента для лимитов. Заголовки вроде X-API-Key не учитываются:
+// они не проверяются, и случайное значение в каждом запросе давало бы новый лимит.
+func (r *Router) rateLimitKey(req *http.Request) string {
+	return "ip:" + r.handler.clientIPResolver.ClientIP(req)
+}
+
+// ceilSeconds округляет длительность вверх до целых секунд
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
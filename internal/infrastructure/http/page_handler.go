@@ -0,0 +1,207 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// PublicPage обрабатывает GET /p/{slug} — страницу со списком ссылок,
+// и GET /p/{slug}/{n} — переход по n-му пункту страницы
+func (h *Handler) PublicPage(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	path, ok := h.extractPathParam(r, "/p/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_page", "Invalid page", nil)
+		return
+	}
+
+	if slug, item, found := strings.Cut(path, "/"); found {
+		position, err := strconv.Atoi(item)
+		if err != nil {
+			h.respondError(w, http.StatusNotFound, "page_not_found", "Page not found", nil)
+			return
+		}
+		h.followPageItem(w, r, slug, position)
+		return
+	}
+
+	page, err := h.pageUseCase.View(r.Context(), usecase.PageViewRequest{
+		Slug:      path,
+		UserAgent: r.Header.Get("User-Agent"),
+		IPAddress: h.clientIPResolver.ClientIP(r),
+	})
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.renderLinkPage(w, page)
+}
+
+// followPageItem переходит по пункту страницы так же, как по короткой ссылке,
+// отмечая переход страницей
+func (h *Handler) followPageItem(w http.ResponseWriter, r *http.Request, slug string, position int) {
+	page, item, err := h.pageUseCase.Item(r.Context(), slug, position)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	visitorID, hasVisitorCookie := h.visitorID(r)
+	h.followLink(w, r, usecase.RedirectRequest{
+		ShortURL:        item.ShortURL,
+		VisitorID:       visitorID,
+		UserAgent:       r.Header.Get("User-Agent"),
+		IPAddress:       h.clientIPResolver.ClientIP(r),
+		Headers:         r.Header,
+		UnlockedVersion: h.linkUnlocker.UnlockedVersion(r, item.ShortURL),
+		PageID:          page.ID,
+	}, hasVisitorCookie)
+}
+
+// renderLinkPage отрисовывает страницу со списком ссылок
+func (h *Handler) renderLinkPage(w http.ResponseWriter, page *entity.Page) {
+	type pageItem struct {
+		Href    string
+		Title   string
+		Icon    string
+		IconURL string
+	}
+
+	items := make([]pageItem, len(page.Items))
+	for i, item := range page.Items {
+		items[i] = pageItem{
+			Href:  fmt.Sprintf("/p/%s/%d", url.PathEscape(page.Slug), i+1),
+			Title: item.Title,
+		}
+		if strings.HasPrefix(item.Icon, "http://") || strings.HasPrefix(item.Icon, "https://") {
+			items[i].IconURL = item.Icon
+		} else {
+			items[i].Icon = item.Icon
+		}
+	}
+
+	h.renderPage(w, http.StatusOK, "page.html", struct {
+		Title       string
+		Description string
+		Items       []pageItem
+	}{
+		Title:       page.Title,
+		Description: page.Description,
+		Items:       items,
+	})
+}
+
+// Pages обрабатывает GET и POST /pages
+func (h *Handler) Pages(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		pages, err := h.pageUseCase.List(r.Context())
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, pages)
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+		defer r.Body.Close()
+
+		var req usecase.PageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+			return
+		}
+
+		page, err := h.pageUseCase.Create(r.Context(), req)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusCreated, page)
+
+	default:
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+	}
+}
+
+// Page обрабатывает GET, PUT и DELETE /pages/{slug} и GET /pages/{slug}/analytics
+func (h *Handler) Page(w http.ResponseWriter, r *http.Request) {
+	slug, ok := h.extractPathParam(r, "/pages/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_page", "Invalid page", nil)
+		return
+	}
+
+	if pageSlug, action, found := strings.Cut(slug, "/"); found {
+		if action != "analytics" {
+			h.respondError(w, http.StatusNotFound, "not_found", "Not found", nil)
+			return
+		}
+		h.pageAnalytics(w, r, pageSlug)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		page, err := h.pageUseCase.Get(r.Context(), slug)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, page)
+
+	case http.MethodPut:
+		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+		defer r.Body.Close()
+
+		var req usecase.PageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+			return
+		}
+		req.Slug = slug
+
+		page, err := h.pageUseCase.Update(r.Context(), req)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, page)
+
+	case http.MethodDelete:
+		if err := h.pageUseCase.Delete(r.Context(), slug); err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+	}
+}
+
+// pageAnalytics обрабатывает GET /pages/{slug}/analytics
+func (h *Handler) pageAnalytics(w http.ResponseWriter, r *http.Request, slug string) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	analytics, err := h.pageUseCase.Analytics(r.Context(), slug)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, analytics)
+}
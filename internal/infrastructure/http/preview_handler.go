@@ -0,0 +1,148 @@
+package http
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+	"github.com/oziev02/Shortener/internal/domain/entity"
+)
+
+// renderPreviewPage отрисовывает страницу предпросмотра: адрес назначения, дату создания и владельца
+func (h *Handler) renderPreviewPage(w http.ResponseWriter, r *http.Request, link *entity.Link) {
+	// Кнопка перехода сохраняет параметры подписи, но не флаг предпросмотра
+	query := r.URL.Query()
+	query.Del("preview")
+	continueURL := &url.URL{Path: "/s/" + link.ShortURL, RawQuery: query.Encode()}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.renderPage(w, http.StatusOK, "preview.html", struct {
+		ShortCode   string
+		OriginalURL string
+		Owner       string
+		CreatedAt   string
+		ContinueURL string
+	}{
+		ShortCode:   link.ShortURL,
+		OriginalURL: link.OriginalURL,
+		Owner:       link.Owner,
+		CreatedAt:   link.CreatedAt.UTC().Format("02.01.2006 15:04 UTC"),
+		ContinueURL: continueURL.String(),
+	})
+}
+
+// renderSocialPreview отвечает боту соцсети страницей с метаданными Open Graph вместо
+// редиректа. Возвращает false, если у ссылки нет превью и нужен обычный переход.
+// Показ превью переходом не считается.
+func (h *Handler) renderSocialPreview(w http.ResponseWriter, r *http.Request, req usecase.RedirectRequest) bool {
+	link, err := h.redirectUseCase.Preview(r.Context(), req)
+	if err != nil {
+		h.handleRedirectError(w, r, req.ShortURL, err)
+		return true
+	}
+
+	og := link.SocialPreview()
+	// До запуска показываются только поля, заданные вручную: загруженные раскрывают адрес назначения
+	if link.IsPending(time.Now()) {
+		og = link.OpenGraph
+	}
+	if og == nil {
+		return false
+	}
+
+	title := og.Title
+	if title == "" {
+		title = link.ShortURL
+	}
+	continueURL := &url.URL{Path: "/s/" + link.ShortURL, RawQuery: r.URL.RawQuery}
+
+	w.Header().Set("Vary", "User-Agent")
+	h.renderPage(w, http.StatusOK, "social_preview.html", struct {
+		Title       string
+		OpenGraph   *entity.OpenGraph
+		ContinueURL string
+	}{
+		Title:       title,
+		OpenGraph:   og,
+		ContinueURL: continueURL.String(),
+	})
+	return true
+}
+
+// renderComingSoonPage отрисовывает страницу «скоро» для ссылки, окно активности которой
+// ещё не открылось. Retry-After подсказывает клиентам, когда повторить запрос.
+func (h *Handler) renderComingSoonPage(w http.ResponseWriter, link *entity.Link) {
+	seconds := ceilSeconds(time.Until(*link.ActiveFrom))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	h.renderPage(w, http.StatusServiceUnavailable, "coming_soon.html", struct {
+		ShortCode  string
+		ActiveFrom string
+		Seconds    int
+	}{
+		ShortCode:  link.ShortURL,
+		ActiveFrom: link.ActiveFrom.UTC().Format("02.01.2006 в 15:04 UTC"),
+		Seconds:    seconds,
+	})
+}
+
+// renderInterstitialPage отрисовывает промежуточную страницу с обратным отсчётом перед редиректом
+func (h *Handler) renderInterstitialPage(w http.ResponseWriter, result *usecase.RedirectResult) {
+	w.Header().Set("Cache-Control", "no-store")
+	h.renderPage(w, http.StatusOK, "interstitial.html", struct {
+		ShortCode string
+		URL       string
+		Seconds   int
+	}{
+		ShortCode: result.Link.ShortURL,
+		URL:       result.URL,
+		Seconds:   result.Link.InterstitialSeconds,
+	})
+}
+
+// renderDeepLinkPage отрисовывает страницу, которая открывает приложение и переходит
+// на запасной адрес, если приложение не открылось. Адрес приложения проверен при
+// сохранении ссылки, поэтому передаётся в шаблон как template.URL.
+func (h *Handler) renderDeepLinkPage(w http.ResponseWriter, target *usecase.DeepLinkTarget) {
+	w.Header().Set("Cache-Control", "no-store")
+	h.renderPage(w, http.StatusOK, "deep_link.html", struct {
+		AppURL      template.URL
+		FallbackURL string
+		DelayMs     int64
+	}{
+		AppURL:      template.URL(target.AppURL),
+		FallbackURL: target.FallbackURL,
+		DelayMs:     DeepLinkFallbackDelay.Milliseconds(),
+	})
+}
+
+// linkOpenGraph обрабатывает GET и POST /links/{short_url}/open-graph: GET возвращает
+// метаданные превью, POST загружает их заново с адреса назначения
+func (h *Handler) linkOpenGraph(w http.ResponseWriter, r *http.Request, shortURL string) {
+	var (
+		resp *usecase.OpenGraphResponse
+		err  error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		resp, err = h.openGraphUseCase.Get(r.Context(), shortURL)
+	case http.MethodPost:
+		resp, err = h.openGraphUseCase.Fetch(r.Context(), shortURL)
+	default:
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+		return
+	}
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+)
+
+// QR обрабатывает GET /qr/{short_url}?format=png|svg&size=&ecc=&margin=&fg=&bg=&logo=
+func (h *Handler) QR(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	shortURL, ok := h.extractPathParam(r, "/qr/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_short_url", "Invalid short URL", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	req := usecase.QRRequest{
+		ShortURL: shortURL,
+		QROptions: usecase.QROptions{
+			Format:     query.Get("format"),
+			ECC:        query.Get("ecc"),
+			Margin:     usecase.DefaultQRMargin,
+			Foreground: query.Get("fg"),
+			Background: query.Get("bg"),
+		},
+	}
+
+	// Числовые и логические параметры необязательны
+	var err error
+	if value := query.Get("size"); value != "" {
+		if req.Size, err = strconv.Atoi(value); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_qr_options", "size must be an integer", err)
+			return
+		}
+	}
+	if value := query.Get("margin"); value != "" {
+		if req.Margin, err = strconv.Atoi(value); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_qr_options", "margin must be an integer", err)
+			return
+		}
+	}
+	if value := query.Get("logo"); value != "" {
+		if req.Logo, err = strconv.ParseBool(value); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_qr_options", "logo must be a boolean", err)
+			return
+		}
+	}
+
+	image, err := h.qrUseCase.Execute(r.Context(), req)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	// ServeContent отвечает 304 на If-None-Match с тем же ETag
+	w.Header().Set("Content-Type", image.ContentType)
+	w.Header().Set("ETag", image.ETag)
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(usecase.QRCacheTTL.Seconds())))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(image.Data))
+}
@@ -2,29 +2,104 @@ package http
 
 import (
 	"net/http"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/infrastructure/ratelimit"
 )
 
 // Router настраивает маршруты
 type Router struct {
-	handler *Handler
+	handler    *Handler
+	adminToken string
+	limiter    ratelimit.Limiter
+	policies   RateLimitPolicies
+}
+
+// NewRouter создаёт новый роутер. adminToken защищает административный API;
+// пустое значение отключает его. Если limiter равен nil, лимиты не применяются.
+func NewRouter(handler *Handler, adminToken string, limiter ratelimit.Limiter, policies RateLimitPolicies) *Router {
+	return &Router{
+		handler:    handler,
+		adminToken: adminToken,
+		limiter:    limiter,
+		policies:   policies,
+	}
 }
 
-// NewRouter создаёт новый роутер
-func NewRouter(handler *Handler) *Router {
-	return &Router{handler: handler}
+// route маршрут HTTP API
+type route struct {
+	pattern string
+	handler http.HandlerFunc
 }
 
 // SetupRoutes настраивает все маршруты
 func (r *Router) SetupRoutes() http.Handler {
 	mux := http.NewServeMux()
+	for _, rt := range r.routes() {
+		mux.HandleFunc(rt.pattern, rt.handler)
+	}
+	return mux
+}
 
-	// API эндпоинты
-	mux.HandleFunc("/shorten", r.handler.Shorten)
-	mux.HandleFunc("/s/", r.handler.Redirect)
-	mux.HandleFunc("/analytics/", r.handler.Analytics)
+// RoutePrefixes возвращает первые сегменты путей всех маршрутов. Алиасы с такими
+// именами заняты маршрутами сервиса и резервируются политикой алиасов.
+func RoutePrefixes() []string {
+	r := &Router{handler: &Handler{}}
 
-	// UI
-	mux.HandleFunc("/", r.handler.ServeUI)
+	seen := make(map[string]bool)
+	var prefixes []string
+	for _, rt := range r.routes() {
+		prefix, _, _ := strings.Cut(strings.TrimPrefix(rt.pattern, "/"), "/")
+		if prefix != "" && !seen[prefix] {
+			seen[prefix] = true
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
 
-	return mux
+// routes возвращает все маршруты сервиса
+func (r *Router) routes() []route {
+	return []route{
+		// API эндпоинты
+		{"/shorten", r.rateLimit(r.policies.Create, r.handler.Shorten)},
+		{"/s/", r.rateLimit(r.policies.Redirect, r.handler.Redirect)},
+		{"/analytics/", r.rateLimit(r.policies.Analytics, r.handler.Analytics)},
+		{"/qr/", r.rateLimit(r.policies.Redirect, r.handler.QR)},
+		{"/convert/", r.rateLimit(r.policies.Redirect, r.handler.Convert)},
+		{"/p/", r.rateLimit(r.policies.Redirect, r.handler.PublicPage)},
+		{"/rules/validate", r.rateLimit(r.policies.Analytics, r.handler.ValidateRules)},
+		{"/utm/build", r.rateLimit(r.policies.Analytics, r.handler.BuildUTM)},
+		{"/aliases/check", r.rateLimit(r.policies.Analytics, r.handler.CheckAlias)},
+
+		// Страница-предупреждение для заблокированных ссылок
+		{"/blocked/", r.rateLimit(r.policies.Redirect, r.handler.Blocked)},
+
+		// Административный API
+		{"/import", r.requireAdmin(r.handler.Import)},
+		{"/import/", r.requireAdmin(r.handler.ImportStatus)},
+		{"/export", r.requireAdmin(r.handler.Export)},
+		{"/admin/rules", r.requireAdmin(r.handler.DomainRules)},
+		{"/admin/rules/", r.requireAdmin(r.handler.DomainRule)},
+		{"/links", r.requireAdmin(r.handler.Links)},
+		{"/links/", r.requireAdmin(r.handler.LinkAction)},
+		{"/campaigns", r.requireAdmin(r.handler.Campaigns)},
+		{"/campaigns/", r.requireAdmin(r.handler.Campaign)},
+		{"/tags", r.requireAdmin(r.handler.Tags)},
+		{"/tags/", r.requireAdmin(r.handler.Tag)},
+		{"/utm/templates", r.requireAdmin(r.handler.UTMTemplates)},
+		{"/utm/templates/", r.requireAdmin(r.handler.UTMTemplate)},
+		{"/utm/report", r.requireAdmin(r.handler.UTMReport)},
+		{"/pages", r.requireAdmin(r.handler.Pages)},
+		{"/pages/", r.requireAdmin(r.handler.Page)},
+		{"/health/subscriptions", r.requireAdmin(r.handler.HealthSubscriptions)},
+		{"/health/subscriptions/", r.requireAdmin(r.handler.HealthSubscription)},
+
+		// Файлы ассоциации домена с мобильными приложениями
+		{"/.well-known/apple-app-site-association", r.handler.AppleAppSiteAssociation},
+		{"/.well-known/assetlinks.json", r.handler.AssetLinks},
+
+		// UI
+		{"/", r.handler.ServeUI},
+	}
 }
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+)
+
+// ValidateRules обрабатывает POST /rules/validate — проверку правил маршрутизации
+// на тестовом запросе с объяснением, почему правило сработало или нет
+func (h *Handler) ValidateRules(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+	defer r.Body.Close()
+
+	var req usecase.ExplainRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+		return
+	}
+
+	resp, err := h.shortenUseCase.ExplainRules(r.Context(), req)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
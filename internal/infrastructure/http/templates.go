@@ -0,0 +1,32 @@
+package http
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"net/http"
+)
+
+// templateFS HTML-шаблоны страниц, встроенные в бинарник
+//
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// pageTemplates разобранные шаблоны страниц
+var pageTemplates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// renderPage отрисовывает HTML-страницу по шаблону
+func (h *Handler) renderPage(w http.ResponseWriter, statusCode int, name string, data interface{}) {
+	// Рендерим в буфер, чтобы при ошибке шаблона не отдать клиенту половину страницы
+	var buf bytes.Buffer
+	if err := pageTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "internal_error", "Internal server error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		h.logger.Error("failed to write page", err, "template", name)
+	}
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+)
+
+// BuildUTM обрабатывает POST /utm/build — сборку адреса с UTM-метками без создания ссылки
+func (h *Handler) BuildUTM(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+	defer r.Body.Close()
+
+	var req usecase.BuildUTMRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+		return
+	}
+
+	resp, err := h.utmUseCase.Build(r.Context(), req)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// UTMTemplates обрабатывает GET и POST /utm/templates
+func (h *Handler) UTMTemplates(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		templates, err := h.utmUseCase.ListTemplates(r.Context())
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, templates)
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+		defer r.Body.Close()
+
+		var req usecase.UTMTemplateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+			return
+		}
+
+		template, err := h.utmUseCase.CreateTemplate(r.Context(), req)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusCreated, template)
+
+	default:
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+	}
+}
+
+// UTMTemplate обрабатывает GET, PUT и DELETE /utm/templates/{name}
+func (h *Handler) UTMTemplate(w http.ResponseWriter, r *http.Request) {
+	name, ok := h.extractPathParam(r, "/utm/templates/")
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid_utm", "Invalid template name", nil)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		template, err := h.utmUseCase.GetTemplate(r.Context(), name)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, template)
+
+	case http.MethodPut:
+		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+		defer r.Body.Close()
+
+		var req usecase.UTMTemplateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid_request_body", "Invalid request body", err)
+			return
+		}
+		req.Name = name
+
+		template, err := h.utmUseCase.UpdateTemplate(r.Context(), req)
+		if err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, template)
+
+	case http.MethodDelete:
+		if err := h.utmUseCase.DeleteTemplate(r.Context(), name); err != nil {
+			h.handleUseCaseError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.respondError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed", nil)
+	}
+}
+
+// UTMReport обрабатывает GET /utm/report?group_by=campaign
+func (h *Handler) UTMReport(w http.ResponseWriter, r *http.Request) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	report, err := h.utmUseCase.Report(r.Context(), r.URL.Query().Get("group_by"))
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
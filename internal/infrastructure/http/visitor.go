@@ -0,0 +1,48 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+const (
+	// visitorCookieName cookie с идентификатором посетителя для A/B-тестов
+	visitorCookieName = "sl_vid"
+	// visitorCookieTTL срок жизни cookie посетителя
+	visitorCookieTTL = 365 * 24 * time.Hour
+)
+
+// visitorID возвращает идентификатор посетителя из cookie. Если cookie нет, используется
+// отпечаток IP-адреса и User-Agent, чтобы вариант сохранялся и у клиентов без cookie.
+func (h *Handler) visitorID(r *http.Request) (string, bool) {
+	if cookie, err := r.Cookie(visitorCookieName); err == nil && isVisitorID(cookie.Value) {
+		return cookie.Value, true
+	}
+
+	sum := sha256.Sum256([]byte(h.clientIPResolver.ClientIP(r) + "\n" + r.Header.Get("User-Agent")))
+	return hex.EncodeToString(sum[:16]), false
+}
+
+// setVisitorCookie закрепляет идентификатор посетителя в cookie
+func (h *Handler) setVisitorCookie(w http.ResponseWriter, r *http.Request, visitorID string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     visitorCookieName,
+		Value:    visitorID,
+		Path:     "/",
+		MaxAge:   int(visitorCookieTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// isVisitorID проверяет формат идентификатора посетителя
+func isVisitorID(value string) bool {
+	if len(value) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
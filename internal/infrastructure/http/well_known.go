@@ -0,0 +1,86 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// WellKnownFiles файлы ассоциации домена с мобильными приложениями: apple-app-site-association
+// для universal links iOS и assetlinks.json для App Links Android. Файлы отдаются на любом
+// домене, который обслуживает сервер.
+type WellKnownFiles struct {
+	appleAppSiteAssociation []byte
+	assetLinks              []byte
+}
+
+// LoadWellKnownFiles читает файлы ассоциации. Пустой путь отключает соответствующий файл.
+// Содержимое проверяется как JSON при запуске, чтобы ошибка в файле не дошла до клиентов.
+func LoadWellKnownFiles(appleAppSiteAssociationPath, assetLinksPath string) (*WellKnownFiles, error) {
+	files := &WellKnownFiles{}
+
+	var err error
+	if files.appleAppSiteAssociation, err = loadJSONFile(appleAppSiteAssociationPath); err != nil {
+		return nil, fmt.Errorf("apple-app-site-association: %w", err)
+	}
+	if files.assetLinks, err = loadJSONFile(assetLinksPath); err != nil {
+		return nil, fmt.Errorf("assetlinks.json: %w", err)
+	}
+
+	return files, nil
+}
+
+// loadJSONFile читает JSON-файл и возвращает его в компактном виде; пустой путь — nil
+func loadJSONFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// AppleAppSiteAssociation обрабатывает GET /.well-known/apple-app-site-association
+func (h *Handler) AppleAppSiteAssociation(w http.ResponseWriter, r *http.Request) {
+	var data []byte
+	if h.wellKnownFiles != nil {
+		data = h.wellKnownFiles.appleAppSiteAssociation
+	}
+	h.serveWellKnown(w, r, data)
+}
+
+// AssetLinks обрабатывает GET /.well-known/assetlinks.json
+func (h *Handler) AssetLinks(w http.ResponseWriter, r *http.Request) {
+	var data []byte
+	if h.wellKnownFiles != nil {
+		data = h.wellKnownFiles.assetLinks
+	}
+	h.serveWellKnown(w, r, data)
+}
+
+// serveWellKnown отдаёт файл ассоциации. Apple и Google не следуют редиректам
+// и требуют Content-Type application/json.
+func (h *Handler) serveWellKnown(w http.ResponseWriter, r *http.Request, data []byte) {
+	if !h.ensureMethod(w, r, http.MethodGet) {
+		return
+	}
+	if data == nil {
+		h.respondError(w, http.StatusNotFound, "not_found", "Not found", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	if _, err := w.Write(data); err != nil {
+		h.logger.Error("failed to write well-known file", err)
+	}
+}
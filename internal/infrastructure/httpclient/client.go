@@ -0,0 +1,97 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+const (
+	// dialTimeout ограничение времени на установку соединения
+	dialTimeout = 10 * time.Second
+
+	// keepAlive период keep-alive для открытых соединений
+	keepAlive = 30 * time.Second
+)
+
+// Dialer устанавливает соединения только с адресами, разрешёнными политикой SSRF.
+// Имя хоста разрешается один раз, проверяются все полученные адреса, и соединение
+// открывается с проверенным адресом, поэтому подмена DNS после проверки URL не помогает.
+type Dialer struct {
+	policy   *service.URLPolicy
+	resolver service.Resolver
+	dialer   net.Dialer
+}
+
+// NewDialer создаёт проверяющий dialer. Если resolver равен nil, используется системный.
+func NewDialer(policy *service.URLPolicy, resolver service.Resolver) *Dialer {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &Dialer{
+		policy:   policy,
+		resolver: resolver,
+		dialer:   net.Dialer{Timeout: dialTimeout, KeepAlive: keepAlive},
+	}
+}
+
+// DialContext разрешает хост, отклоняет внутренние адреса и подключается к первому доступному
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := d.resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, &net.DNSError{Err: "no addresses", Name: host, IsNotFound: true}
+	}
+
+	// Как и политика, отклоняем хост целиком, если хотя бы один его адрес внутренний
+	for _, ip := range ips {
+		if err := d.policy.CheckIP(ip); err != nil {
+			return nil, &net.OpError{Op: "dial", Net: network, Addr: &net.TCPAddr{IP: ip}, Err: err}
+		}
+	}
+
+	var errs []error
+	for _, ip := range ips {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// New создаёт клиента для запросов к адресам, заданным пользователями: проверке доступности,
+// загрузке превью и отправке уведомлений. Прокси из окружения не используется, так как
+// адрес назначения тогда разрешал бы прокси, и проверка не имела бы смысла.
+func New(timeout time.Duration, policy *service.URLPolicy) *http.Client {
+	return newClient(timeout, NewDialer(policy, nil))
+}
+
+// newClient создаёт клиента, подключающегося через dialer
+func newClient(timeout time.Duration, dialer *Dialer) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
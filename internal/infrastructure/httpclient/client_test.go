@@ -0,0 +1,102 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+// fakeResolver разрешает имена по таблице
+type fakeResolver map[string]string
+
+func (r fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+}
+
+func TestDialerBlocksPrivateAddresses(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	// Имя проходит проверку URL без разрешения имён (SSRF_RESOLVE_HOSTS=false),
+	// но при подключении разрешается в loopback
+	policy := service.NewURLPolicy(true, nil)
+	rawURL := "http://rebind.test:" + port + "/"
+	if err := policy.Check(context.Background(), rawURL); err != nil {
+		t.Fatalf("Check = %v, want the name to pass", err)
+	}
+
+	resolver := fakeResolver{"rebind.test": "127.0.0.1"}
+	tests := []struct {
+		name   string
+		rawURL string
+	}{
+		{"hostname resolving to loopback", rawURL},
+		{"loopback literal", server.URL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newClient(0, NewDialer(policy, resolver)).Get(tt.rawURL)
+			if !errors.Is(err, service.ErrPrivateTarget) {
+				t.Fatalf("Get = %v, want ErrPrivateTarget", err)
+			}
+		})
+	}
+	if hits != 0 {
+		t.Fatalf("server got %d requests, want none", hits)
+	}
+
+	// С отключённой защитой запрос проходит через тот же dialer
+	resp, err := newClient(0, NewDialer(service.NewURLPolicy(false, nil), resolver)).Get(rawURL)
+	if err != nil {
+		t.Fatalf("Get = %v", err)
+	}
+	resp.Body.Close()
+	if hits != 1 {
+		t.Fatalf("server got %d requests, want 1", hits)
+	}
+}
+
+func TestDialerRejectsHostWithAnyPrivateAddress(t *testing.T) {
+	resolver := multiResolver{"mixed.test": {"93.184.216.34", "169.254.169.254"}}
+	dialer := NewDialer(service.NewURLPolicy(true, nil), resolver)
+
+	_, err := dialer.DialContext(context.Background(), "tcp", "mixed.test:80")
+	if !errors.Is(err, service.ErrPrivateTarget) {
+		t.Fatalf("DialContext = %v, want ErrPrivateTarget", err)
+	}
+}
+
+func TestNewDisablesEnvironmentProxy(t *testing.T) {
+	client := New(0, service.NewURLPolicy(true, nil))
+	transport := client.Transport.(*http.Transport)
+	if transport.Proxy != nil {
+		proxy, _ := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "http", Host: "example.com"}})
+		t.Fatalf("Proxy = %v, want none", proxy)
+	}
+}
+
+// multiResolver разрешает имя в несколько адресов
+type multiResolver map[string][]string
+
+func (r multiResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	var addrs []net.IPAddr
+	for _, ip := range r[host] {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
@@ -0,0 +1,193 @@
+package opengraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/service"
+)
+
+const (
+	// userAgent заголовок User-Agent запросов; часть сайтов отдаёт метаданные только ботам превью
+	userAgent = "Mozilla/5.0 (compatible; ShortenerPreview/1.0)"
+
+	// maxRedirects максимальная длина цепочки редиректов
+	maxRedirects = 10
+
+	// maxPageSize объём страницы, в котором ищутся метаданные; они находятся в <head>
+	maxPageSize = 1 << 20
+)
+
+// Fetcher загружает метаданные Open Graph страницы. Каждый адрес цепочки редиректов
+// проверяется политикой SSRF, так как запрос выполняет сервер.
+type Fetcher struct {
+	client *http.Client
+	policy *service.URLPolicy
+}
+
+var _ usecase.OpenGraphFetcher = (*Fetcher)(nil)
+
+// NewFetcher создаёт загрузчик. Если client равен nil, используется клиент по умолчанию;
+// таймаут запроса задаётся в client.Timeout. В работе нужен клиент из httpclient.New,
+// который проверяет адрес при подключении.
+func NewFetcher(client *http.Client, policy *service.URLPolicy) *Fetcher {
+	var c http.Client
+	if client != nil {
+		c = *client
+	}
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return policy.Check(req.Context(), req.URL.String())
+	}
+
+	return &Fetcher{client: &c, policy: policy}
+}
+
+// Fetch загружает страницу и извлекает из <head> теги og:title, og:description и og:image.
+// Если тегов Open Graph нет, используются теги twitter:*, <title> и meta description.
+// Для ответа не в HTML возвращается пустая структура.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*entity.OpenGraph, error) {
+	if err := f.policy.Check(ctx, rawURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("destination responded with status %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.Contains(contentType, "html") {
+		return &entity.OpenGraph{}, nil
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, maxPageSize), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode page: %w", err)
+	}
+
+	return parse(body, resp.Request.URL)
+}
+
+// parse извлекает метаданные из HTML; относительный адрес изображения разрешается от base
+func parse(r io.Reader, base *url.URL) (*entity.OpenGraph, error) {
+	var og, fallback entity.OpenGraph
+	// <title> используется, только если нет twitter:title, независимо от порядка тегов
+	var pageTitle string
+	tokenizer := html.NewTokenizer(r)
+	inTitle := false
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("failed to parse page: %w", err)
+			}
+			return result(og, fallback, pageTitle, base), nil
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "meta":
+				applyMeta(&og, &fallback, token.Attr)
+			case "title":
+				inTitle = true
+			case "body":
+				// Метаданные находятся в <head>, остальную страницу не читаем
+				return result(og, fallback, pageTitle, base), nil
+			}
+
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				inTitle = false
+			case "head":
+				return result(og, fallback, pageTitle, base), nil
+			}
+
+		case html.TextToken:
+			if inTitle && pageTitle == "" {
+				pageTitle = strings.TrimSpace(string(tokenizer.Text()))
+			}
+		}
+	}
+}
+
+// applyMeta разбирает тег <meta>: og:* записываются в og, twitter:* и description — в fallback
+func applyMeta(og, fallback *entity.OpenGraph, attrs []html.Attribute) {
+	var key, content string
+	for _, attr := range attrs {
+		switch strings.ToLower(attr.Key) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(attr.Val))
+			}
+		case "content":
+			content = strings.TrimSpace(attr.Val)
+		}
+	}
+	if content == "" {
+		return
+	}
+
+	switch key {
+	case "og:title":
+		setOnce(&og.Title, content)
+	case "og:description":
+		setOnce(&og.Description, content)
+	case "og:image", "og:image:url", "og:image:secure_url":
+		setOnce(&og.Image, content)
+	case "twitter:title":
+		setOnce(&fallback.Title, content)
+	case "twitter:description", "description":
+		setOnce(&fallback.Description, content)
+	case "twitter:image", "twitter:image:src":
+		setOnce(&fallback.Image, content)
+	}
+}
+
+// result дополняет og значениями из fallback и заголовком страницы и разрешает адрес изображения
+func result(og, fallback entity.OpenGraph, pageTitle string, base *url.URL) *entity.OpenGraph {
+	setOnce(&fallback.Title, pageTitle)
+	merged := fallback.Merge(og)
+	if merged.Image != "" {
+		image, err := base.Parse(merged.Image)
+		if err != nil {
+			merged.Image = ""
+		} else {
+			merged.Image = image.String()
+		}
+	}
+	return &merged
+}
+
+// setOnce записывает значение, если поле ещё не заполнено: действует первый тег страницы
+func setOnce(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
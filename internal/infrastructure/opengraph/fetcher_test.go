@@ -0,0 +1,149 @@
+package opengraph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/oziev02/Shortener/internal/domain/entity"
+	"github.com/oziev02/Shortener/internal/domain/service"
+	"github.com/oziev02/Shortener/internal/infrastructure/httpclient"
+)
+
+// serve запускает сервер, отдающий body с заданным Content-Type
+func serve(t *testing.T, contentType, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetch(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        entity.OpenGraph
+	}{
+		{
+			name:        "open graph tags",
+			contentType: "text/html; charset=utf-8",
+			body: `<html><head>
+				<title>Page title</title>
+				<meta property="og:title" content="OG title">
+				<meta property="og:description" content="OG description">
+				<meta property="og:image" content="/images/cover.png">
+				<meta name="twitter:title" content="Twitter title">
+				</head><body><meta property="og:title" content="ignored"></body></html>`,
+			want: entity.OpenGraph{Title: "OG title", Description: "OG description", Image: "/images/cover.png"},
+		},
+		{
+			name:        "title and description fallback",
+			contentType: "text/html",
+			body: `<html><head>
+				<title> Page title </title>
+				<meta name="description" content="Meta description">
+				</head></html>`,
+			want: entity.OpenGraph{Title: "Page title", Description: "Meta description"},
+		},
+		{
+			name:        "twitter fallback",
+			contentType: "text/html",
+			body: `<head><title>Page title</title>
+				<meta name="twitter:title" content="Twitter title">
+				<meta name="twitter:image" content="https://cdn.example.com/t.png"></head>`,
+			want: entity.OpenGraph{Title: "Twitter title", Image: "https://cdn.example.com/t.png"},
+		},
+		{
+			name:        "windows-1251 page",
+			contentType: "text/html; charset=windows-1251",
+			body:        "<head><meta property=\"og:title\" content=\"\xcf\xf0\xe8\xe2\xe5\xf2\"></head>",
+			want:        entity.OpenGraph{Title: "Привет"},
+		},
+		{
+			name:        "not html",
+			contentType: "application/json",
+			body:        `{"og:title": "not a page"}`,
+			want:        entity.OpenGraph{},
+		},
+		{
+			name:        "oversized page",
+			contentType: "text/html",
+			body: "<html><head><meta property=\"og:description\" content=\"Early\">" +
+				"<script>" + strings.Repeat("x", maxPageSize) + "</script>" +
+				"<meta property=\"og:title\" content=\"Too late\"></head></html>",
+			want: entity.OpenGraph{Description: "Early"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := serve(t, tt.contentType, tt.body)
+
+			og, err := NewFetcher(server.Client(), nil).Fetch(context.Background(), server.URL+"/page")
+			if err != nil {
+				t.Fatalf("Fetch = %v", err)
+			}
+
+			want := tt.want
+			if strings.HasPrefix(want.Image, "/") {
+				want.Image = server.URL + want.Image
+			}
+			if *og != want {
+				t.Fatalf("Fetch = %+v, want %+v", *og, want)
+			}
+		})
+	}
+}
+
+func TestFetchErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := NewFetcher(server.Client(), nil).Fetch(context.Background(), server.URL); err == nil {
+		t.Fatal("Fetch = nil error, want status error")
+	}
+}
+
+func TestFetchFollowsRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/short" {
+			http.Redirect(w, r, "/article/", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<head><meta property="og:image" content="cover.png"></head>`))
+	}))
+	defer server.Close()
+
+	og, err := NewFetcher(server.Client(), nil).Fetch(context.Background(), server.URL+"/short")
+	if err != nil {
+		t.Fatalf("Fetch = %v", err)
+	}
+	// Относительный адрес разрешается от итогового адреса после редиректа
+	if want := server.URL + "/article/cover.png"; og.Image != want {
+		t.Fatalf("Image = %q, want %q", og.Image, want)
+	}
+}
+
+func TestFetchChecksDialedAddress(t *testing.T) {
+	server := serve(t, "text/html", `<head><meta property="og:title" content="Internal"></head>`)
+
+	// Загрузчику политика не передана, поэтому адрес может отклонить только клиент при подключении
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: httpclient.NewDialer(service.NewURLPolicy(true, nil), nil).DialContext,
+	}}
+
+	_, err := NewFetcher(client, nil).Fetch(context.Background(), server.URL)
+	if !errors.Is(err, service.ErrPrivateTarget) {
+		t.Fatalf("Fetch = %v, want ErrPrivateTarget", err)
+	}
+}
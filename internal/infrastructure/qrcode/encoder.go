@@ -0,0 +1,248 @@
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // поддержка логотипов в JPEG
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/oziev02/Shortener/internal/application/usecase"
+	qr "github.com/skip2/go-qrcode"
+)
+
+// logoRatio доля ширины кода, которую занимает логотип вместе с подложкой
+const logoRatio = 0.22
+
+// Encoder рисует QR-коды в PNG и SVG без обращения к внешним сервисам
+type Encoder struct {
+	logo    image.Image
+	logoPNG []byte
+}
+
+// NewEncoder создаёт кодировщик. logo может быть nil, тогда наложение логотипа недоступно.
+func NewEncoder(logo image.Image) (*Encoder, error) {
+	e := &Encoder{logo: logo}
+	if logo != nil {
+		// В SVG логотип встраивается как data URI в PNG
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, logo); err != nil {
+			return nil, fmt.Errorf("failed to encode logo: %w", err)
+		}
+		e.logoPNG = buf.Bytes()
+	}
+	return e, nil
+}
+
+// LoadLogo читает логотип в формате PNG или JPEG
+func LoadLogo(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	logo, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode logo: %w", err)
+	}
+	return logo, nil
+}
+
+// HasLogo сообщает, настроен ли логотип
+func (e *Encoder) HasLogo() bool {
+	return e.logo != nil
+}
+
+// Encode рисует QR-код для content
+func (e *Encoder) Encode(content string, opts usecase.QROptions) ([]byte, error) {
+	level, err := recoveryLevel(opts.ECC)
+	if err != nil {
+		return nil, err
+	}
+	fg, err := parseHexColor(opts.Foreground)
+	if err != nil {
+		return nil, err
+	}
+	bg, err := parseHexColor(opts.Background)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Logo && e.logo == nil {
+		return nil, fmt.Errorf("logo is not configured")
+	}
+
+	code, err := qr.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+	// Свободная зона рисуется самостоятельно, чтобы её ширину можно было настроить
+	code.DisableBorder = true
+	modules := code.Bitmap()
+
+	switch opts.Format {
+	case usecase.QRFormatSVG:
+		return e.renderSVG(modules, opts), nil
+	case usecase.QRFormatPNG:
+		return e.renderPNG(modules, opts, fg, bg)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", opts.Format)
+	}
+}
+
+// layout вычисляет размер модуля в пикселях и отступ, центрирующий код в изображении
+func layout(modules [][]bool, opts usecase.QROptions) (scale, offset, size int) {
+	total := len(modules) + 2*opts.Margin
+	scale = opts.Size / total
+	if scale < 1 {
+		// Код не помещается в запрошенный размер — увеличиваем изображение
+		scale = 1
+	}
+	size = opts.Size
+	if total*scale > size {
+		size = total * scale
+	}
+	offset = (size - len(modules)*scale) / 2
+	return scale, offset, size
+}
+
+// renderPNG рисует код в PNG
+func (e *Encoder) renderPNG(modules [][]bool, opts usecase.QROptions, fg, bg color.RGBA) ([]byte, error) {
+	scale, offset, size := layout(modules, opts)
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: bg}, image.Point{}, draw.Src)
+	fill := &image.Uniform{C: fg}
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			rect := image.Rect(offset+x*scale, offset+y*scale, offset+(x+1)*scale, offset+(y+1)*scale)
+			draw.Draw(img, rect, fill, image.Point{}, draw.Src)
+		}
+	}
+
+	if opts.Logo {
+		box := logoBox(len(modules)*scale, offset)
+		draw.Draw(img, box, &image.Uniform{C: bg}, image.Point{}, draw.Src)
+		inner := box.Inset(box.Dx() / 10)
+		logo := scaleToFit(e.logo, inner.Dx(), inner.Dy())
+		// Центрируем логотип внутри подложки с сохранением пропорций
+		at := image.Pt(
+			inner.Min.X+(inner.Dx()-logo.Bounds().Dx())/2,
+			inner.Min.Y+(inner.Dy()-logo.Bounds().Dy())/2,
+		)
+		draw.Draw(img, logo.Bounds().Add(at), logo, image.Point{}, draw.Over)
+	}
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderSVG рисует код в SVG; соседние модули строки объединяются в один прямоугольник
+func (e *Encoder) renderSVG(modules [][]bool, opts usecase.QROptions) []byte {
+	scale, offset, size := layout(modules, opts)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, size, size)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`, size, size, opts.Background)
+	fmt.Fprintf(&b, `<path fill="%s" d="`, opts.Foreground)
+	for y, row := range modules {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			run := 1
+			for x+run < len(row) && row[x+run] {
+				run++
+			}
+			fmt.Fprintf(&b, "M%d %dh%dv%dh-%dz", offset+x*scale, offset+y*scale, run*scale, scale, run*scale)
+			x += run
+		}
+	}
+	b.WriteString(`"/>`)
+
+	if opts.Logo {
+		box := logoBox(len(modules)*scale, offset)
+		inner := box.Inset(box.Dx() / 10)
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`,
+			box.Min.X, box.Min.Y, box.Dx(), box.Dy(), opts.Background)
+		fmt.Fprintf(&b, `<image x="%d" y="%d" width="%d" height="%d" href="data:image/png;base64,%s"/>`,
+			inner.Min.X, inner.Min.Y, inner.Dx(), inner.Dy(), base64.StdEncoding.EncodeToString(e.logoPNG))
+	}
+
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+// logoBox возвращает квадрат подложки логотипа в центре кода
+func logoBox(codeSize, offset int) image.Rectangle {
+	side := int(float64(codeSize) * logoRatio)
+	start := offset + (codeSize-side)/2
+	return image.Rect(start, start, start+side, start+side)
+}
+
+// scaleToFit масштабирует изображение методом ближайшего соседа, сохраняя пропорции
+func scaleToFit(src image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 || maxWidth <= 0 || maxHeight <= 0 {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0))
+	}
+
+	width, height := maxWidth, bounds.Dy()*maxWidth/bounds.Dx()
+	if height > maxHeight {
+		width, height = bounds.Dx()*maxHeight/bounds.Dy(), maxHeight
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dst.Set(x, y, src.At(bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height))
+		}
+	}
+	return dst
+}
+
+// recoveryLevel преобразует уровень коррекции ошибок
+func recoveryLevel(ecc string) (qr.RecoveryLevel, error) {
+	switch ecc {
+	case "L":
+		return qr.Low, nil
+	case "M", "":
+		return qr.Medium, nil
+	case "Q":
+		return qr.High, nil
+	case "H":
+		return qr.Highest, nil
+	default:
+		return 0, fmt.Errorf("unknown error correction level %q", ecc)
+	}
+}
+
+// parseHexColor разбирает цвет в формате #rrggbb
+func parseHexColor(value string) (color.RGBA, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(value, "#"))
+	if err != nil || len(raw) != 3 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", value)
+	}
+	return color.RGBA{R: raw[0], G: raw[1], B: raw[2], A: 0xff}, nil
+}
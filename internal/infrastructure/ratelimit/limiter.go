@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Policy политика ограничения частоты запросов (token bucket).
+// Корзина вмещает Burst токенов и пополняется на Limit токенов за Period.
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+// Enabled сообщает, задана ли политика
+func (p Policy) Enabled() bool {
+	return p.Limit > 0 && p.Period > 0
+}
+
+// rate скорость пополнения корзины в токенах в секунду
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+// capacity вместимость корзины
+func (p Policy) capacity() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Limit
+}
+
+// Result результат проверки лимита
+type Result struct {
+	Allowed bool
+	// Limit вместимость корзины
+	Limit int
+	// Remaining сколько запросов ещё можно сделать без ожидания
+	Remaining int
+	// RetryAfter через сколько появится следующий токен (для отклонённых запросов)
+	RetryAfter time.Duration
+	// ResetAfter через сколько корзина заполнится полностью
+	ResetAfter time.Duration
+}
+
+// Limiter проверяет, можно ли выполнить запрос с данным ключом
+type Limiter interface {
+	Allow(ctx context.Context, key string, policy Policy) (Result, error)
+}
+
+// secondsToDuration переводит секунды в time.Duration
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval как часто удаляются заполненные (неактивные) корзины
+const memorySweepInterval = time.Minute
+
+// bucket состояние корзины токенов
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full момент, когда корзина заполнится и её можно удалить
+	full time.Time
+}
+
+// MemoryLimiter хранит корзины в памяти процесса; подходит для одного узла
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryLimiter создаёт новый in-memory лимитер
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow списывает токен из корзины ключа, если он есть
+func (m *MemoryLimiter) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	now := time.Now()
+	rate := policy.rate()
+	capacity := float64(policy.capacity())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweepLocked(now)
+
+	b, ok := m.buckets[policy.Name+":"+key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		m.buckets[policy.Name+":"+key] = b
+	}
+
+	// Пополняем корзину за прошедшее время
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	result := Result{Limit: policy.capacity()}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / rate)
+	}
+
+	result.Remaining = int(math.Floor(b.tokens))
+	result.ResetAfter = secondsToDuration((capacity - b.tokens) / rate)
+	b.full = now.Add(result.ResetAfter)
+
+	return result, nil
+}
+
+// sweepLocked удаляет корзины, которые уже заполнились, вызывается под блокировкой
+func (m *MemoryLimiter) sweepLocked(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	for key, b := range m.buckets {
+		if now.After(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastSweep = now
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript атомарно пополняет корзину и списывает токен.
+// KEYS[1] - ключ корзины; ARGV[1] - скорость (токенов в секунду); ARGV[2] - вместимость.
+// Возвращает {allowed, remaining, retry_after_ms, reset_after_ms}.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+	tokens = capacity
+	ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry_after = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry_after = (1 - tokens) / rate
+end
+
+local reset_after = (capacity - tokens) / rate
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(reset_after * 1000) + 1000)
+
+return {allowed, math.floor(tokens), math.ceil(retry_after * 1000), math.ceil(reset_after * 1000)}
+`)
+
+// RedisLimiter хранит корзины в Redis, поэтому лимиты общие для всех узлов
+type RedisLimiter struct {
+	client *redis.Client
+}
+
+// NewRedisLimiter создаёт новый лимитер на основе Redis
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+// Allow списывает токен из корзины ключа, если он есть
+func (r *RedisLimiter) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	values, err := tokenBucketScript.Run(ctx, r.client,
+		[]string{"ratelimit:" + policy.Name + ":" + key},
+		policy.rate(),
+		policy.capacity(),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      policy.capacity(),
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}